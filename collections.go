@@ -0,0 +1,34 @@
+package converterandformatter
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/savannahghi/firebasetools"
+)
+
+// CollectionName returns the name of the Firestore collection that backs the
+// supplied base collection name e.g OTPCollectionName.
+//
+// The name carries the environment suffix from ROOT_COLLECTION_SUFFIX and,
+// when the COLLECTION_NAMESPACE environment variable is set, is prefixed
+// with that tenant namespace. Every collection this package reads or writes
+// is named through this function so that data from different environments
+// and tenants never shares a collection.
+func CollectionName(c string) string {
+	return NamespacedCollectionName(os.Getenv(CollectionNamespaceEnvVarName), c)
+}
+
+// NamespacedCollectionName returns the environment suffixed name of a
+// collection prefixed with an explicit tenant namespace.
+//
+// An empty namespace leaves the suffixed name unprefixed.
+func NamespacedCollectionName(namespace, c string) string {
+	suffixed := firebasetools.SuffixCollection(c)
+	namespace = strings.TrimSpace(namespace)
+	if namespace == "" {
+		return suffixed
+	}
+	return fmt.Sprintf("%s_%s", namespace, suffixed)
+}
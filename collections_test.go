@@ -0,0 +1,145 @@
+package converterandformatter_test
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	uuid "github.com/kevinburke/go.uuid"
+	"github.com/savannahghi/converterandformatter"
+	"github.com/savannahghi/firebasetools"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestCollectionName(t *testing.T) {
+	collections := map[string]string{
+		converterandformatter.OTPCollectionName:                  "otps_bewell_staging",
+		converterandformatter.PhoneOptInCollectionName:           "phone_opt_ins_bewell_staging",
+		converterandformatter.PhoneOptInPreferenceCollectionName: "phone_opt_in_preferences_bewell_staging",
+		converterandformatter.USSDSessionCollectionName:          "ussd_signup_sessions_bewell_staging",
+	}
+
+	existing, wasSet := os.LookupEnv(converterandformatter.CollectionNamespaceEnvVarName)
+	defer func() {
+		if wasSet {
+			os.Setenv(converterandformatter.CollectionNamespaceEnvVarName, existing)
+			return
+		}
+		os.Unsetenv(converterandformatter.CollectionNamespaceEnvVarName)
+	}()
+
+	os.Unsetenv(converterandformatter.CollectionNamespaceEnvVarName)
+	for base, want := range collections {
+		assert.Equal(t, want, converterandformatter.CollectionName(base))
+	}
+
+	os.Setenv(converterandformatter.CollectionNamespaceEnvVarName, "tenant")
+	for base, want := range collections {
+		assert.Equal(t, "tenant_"+want, converterandformatter.CollectionName(base))
+	}
+}
+
+func TestNamespacedCollectionName(t *testing.T) {
+	tests := []struct {
+		name      string
+		namespace string
+		want      string
+	}{
+		{
+			name:      "no namespace",
+			namespace: "",
+			want:      "otps_bewell_staging",
+		},
+		{
+			name:      "blank namespace",
+			namespace: "  ",
+			want:      "otps_bewell_staging",
+		},
+		{
+			name:      "tenant namespace",
+			namespace: "acme",
+			want:      "acme_otps_bewell_staging",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := converterandformatter.NamespacedCollectionName(
+				tt.namespace, converterandformatter.OTPCollectionName)
+			if got != tt.want {
+				t.Errorf("NamespacedCollectionName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCollectionNamespace_Firestore(t *testing.T) {
+	ctx := context.Background()
+	fc, err := firebasetools.GetFirestoreClient(ctx)
+	if err != nil {
+		t.Fatalf("unable to initialize Firestore client: %v", err)
+	}
+
+	existing, wasSet := os.LookupEnv(converterandformatter.CollectionNamespaceEnvVarName)
+	defer func() {
+		if wasSet {
+			os.Setenv(converterandformatter.CollectionNamespaceEnvVarName, existing)
+			return
+		}
+		os.Unsetenv(converterandformatter.CollectionNamespaceEnvVarName)
+	}()
+	namespace := "test" + strings.ReplaceAll(uuid.NewV4().String(), "-", "")[:8]
+	os.Setenv(converterandformatter.CollectionNamespaceEnvVarName, namespace)
+
+	namespaced := func(c string) string {
+		return converterandformatter.NamespacedCollectionName(namespace, c)
+	}
+	shared := func(c string) string {
+		return converterandformatter.NamespacedCollectionName("", c)
+	}
+	msisdn := converterandformatter.NewFaker(time.Now().UnixNano()).MSISDN()
+
+	// USSD sessions
+	sessionID := uuid.NewV4().String()
+	_, err = converterandformatter.StartUSSDSession(sessionID, msisdn, "*384*123#", "63902", fc)
+	assert.Nil(t, err)
+	_, err = fc.Collection(
+		namespaced(converterandformatter.USSDSessionCollectionName)).Doc(sessionID).Get(ctx)
+	assert.Nil(t, err)
+	_, err = fc.Collection(
+		shared(converterandformatter.USSDSessionCollectionName)).Doc(sessionID).Get(ctx)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	// phone opt ins saved after validation
+	_, err = converterandformatter.ValidateAndSaveMSISDN(msisdn, sessionID, true, true, fc)
+	assert.Nil(t, err)
+	docs, err := fc.Collection(namespaced(converterandformatter.PhoneOptInCollectionName)).Where(
+		"msisdn", "==", msisdn).Documents(ctx).GetAll()
+	assert.Nil(t, err)
+	assert.NotEmpty(t, docs)
+	docs, err = fc.Collection(shared(converterandformatter.PhoneOptInCollectionName)).Where(
+		"msisdn", "==", msisdn).Documents(ctx).GetAll()
+	assert.Nil(t, err)
+	assert.Empty(t, docs)
+
+	// preferences changed by an opt out SMS
+	_, err = converterandformatter.SetOptInPreference(
+		msisdn, converterandformatter.OptInChannelSMS, converterandformatter.OptInTopicAppointments, true, fc)
+	assert.Nil(t, err)
+	_, err = converterandformatter.HandleOptInSMS(msisdn, "STOP", fc)
+	assert.Nil(t, err)
+	preferenceID := converterandformatter.OptInPreferenceID(
+		msisdn, converterandformatter.OptInChannelSMS, converterandformatter.OptInTopicAppointments)
+	doc, err := fc.Collection(
+		namespaced(converterandformatter.PhoneOptInPreferenceCollectionName)).Doc(preferenceID).Get(ctx)
+	assert.Nil(t, err)
+	var preference converterandformatter.PhoneOptInPreference
+	assert.Nil(t, doc.DataTo(&preference))
+	assert.False(t, preference.OptedIn)
+	_, err = fc.Collection(
+		shared(converterandformatter.PhoneOptInPreferenceCollectionName)).Doc(preferenceID).Get(ctx)
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
@@ -3,6 +3,10 @@ package converterandformatter
 const (
 	defaultRegion = "KE"
 
+	// CollectionNamespaceEnvVarName is the name of the optional environment
+	// variable that holds a tenant namespace for Firestore collection names
+	CollectionNamespaceEnvVarName = "COLLECTION_NAMESPACE"
+
 	// OTPCollectionName is the name of the collection used to persist single
 	// use verification codes on Firebase
 	OTPCollectionName = "otps"
@@ -10,6 +14,14 @@ const (
 	// PhoneOptInCollectionName ...
 	PhoneOptInCollectionName = "phone_opt_ins"
 
+	// PhoneOptInPreferenceCollectionName is the name of the collection used to
+	// persist per channel and per topic communication consent
+	PhoneOptInPreferenceCollectionName = "phone_opt_in_preferences"
+
+	// USSDMenuStateCollectionName is the name of the collection used to
+	// persist the position of USSD sessions in declarative menus
+	USSDMenuStateCollectionName = "ussd_menu_states"
+
 	//USSDSessionCollectionName ...
 	USSDSessionCollectionName = "ussd_signup_sessions"
 )
@@ -4,13 +4,12 @@ import (
 	"crypto/rand"
 	"encoding/json"
 	"fmt"
-	"log"
 	"math"
 	"math/big"
+	"reflect"
+	"sort"
 	"strconv"
 	"time"
-
-	"github.com/savannahghi/serverutils"
 )
 
 // StructToMap converts an object (struct) to a map.
@@ -18,7 +17,7 @@ import (
 // WARNING: int inputs are converted to floats in the output map. This is an
 // unintended consequence of converting through JSON.
 //
-// In future, this should be deprecated.
+// Deprecated: use StructToTypedMap, which preserves the types of values.
 func StructToMap(item interface{}) (map[string]interface{}, error) {
 	bs, err := json.Marshal(item)
 	if err != nil {
@@ -39,34 +38,30 @@ func GenerateRandomWithNDigits(numberOfDigits int) (string, error) {
 	return strconv.FormatInt(value.Int64(), 10), nil
 }
 
-// GenerateRandomEmail allows us to get "unique" emails while still keeping
-// one main be.well@bewell.co.ke email account
+// GenerateRandomEmail allows us to get unique emails while still keeping
+// one main be.well@bewell.co.ke email account.
+//
+// Use a TestEmailGenerator to generate emails for a different mailbox.
 func GenerateRandomEmail() string {
-	return fmt.Sprintf("be.well+%v@bewell.co.ke", time.Now().Unix())
+	return defaultTestEmailGenerator.Generate()
+}
+
+var defaultTestEmailGenerator = &TestEmailGenerator{
+	mailbox: defaultTestEmailMailbox,
+	domain:  defaultTestEmailDomain,
+	suffix:  TestEmailSuffixUUID,
 }
 
 // ConvertInterfaceMap converts a map[string]interface{} to a map[string]string.
 //
-// Any conversion errors are written out to the output map instead of being
-// returned as error values.
+// It is ToStringMap with StringMapSkip: keys whose values are not strings are
+// left out of the output map.
 //
-// New code is discouraged from using this function.
+// Deprecated: use ToStringMap, which lets the caller choose how values that
+// are not strings are handled.
 func ConvertInterfaceMap(inp map[string]interface{}) map[string]string {
-	out := make(map[string]string)
-	if inp == nil {
-		return out
-	}
-	for k, v := range inp {
-		val, ok := v.(string)
-		if !ok {
-			val = fmt.Sprintf("invalid string value: %#v", v)
-			if serverutils.IsDebug() {
-				log.Printf(
-					"non string value in map[string]interface{} that is to be converted into map[string]string: %#v", v)
-			}
-		}
-		out[k] = val
-	}
+	// StringMapSkip never rejects a map
+	out, _ := ToStringMap(inp, StringMapSkip)
 	return out
 }
 
@@ -75,29 +70,111 @@ func ConvertInterfaceMap(inp map[string]interface{}) map[string]string {
 //
 // It is used to convert a GraphQL (gqlgen) input Map to a map of strings for APIs
 // that need map[string]string.
+//
+// Deprecated: use ToStringMap with StringMapStrict.
 func MapInterfaceToMapString(in map[string]interface{}) (map[string]string, error) {
+	return ToStringMap(in, StringMapStrict)
+}
+
+// StringMapMode decides what ToStringMap does with values that are not
+// strings
+type StringMapMode int
+
+// the ways in which ToStringMap can treat values that are not strings
+const (
+	// StringMapStrict rejects the map if any value is not a string
+	StringMapStrict StringMapMode = iota
+
+	// StringMapSkip leaves out the keys whose values are not strings
+	StringMapSkip
+
+	// StringMapLenient formats numbers, booleans, times and fmt.Stringer
+	// values as strings and rejects the map if any other value is found
+	StringMapLenient
+)
+
+// ToStringMap converts a map with interface{} values, such as a gqlgen Map
+// input, to a map[string]string.
+//
+// When the map is rejected, every offending key is reported in the returned
+// FieldErrors rather than only the first one.
+func ToStringMap(in map[string]interface{}, mode StringMapMode) (map[string]string, error) {
 	out := map[string]string{}
-	for k, v := range in {
-		s, ok := v.(string)
-		if !ok {
-			return nil, fmt.Errorf("%v (%T) is not a string", v, v)
+	errs := FieldErrors{}
+	keys := make([]string, 0, len(in))
+	for k := range in {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		v := in[k]
+		if s, ok := v.(string); ok {
+			out[k] = s
+			continue
 		}
-		out[k] = s
+		switch mode {
+		case StringMapSkip:
+			continue
+		case StringMapLenient:
+			if s, ok := formatScalar(v); ok {
+				out[k] = s
+				continue
+			}
+		}
+		errs = append(errs, &FieldError{
+			Path: k,
+			Err:  fmt.Errorf("%v (%T) is not a string", v, v),
+		})
+	}
+	if len(errs) > 0 {
+		return nil, errs
 	}
 	return out, nil
 }
 
+// formatScalar formats numbers, booleans, times and fmt.Stringer values as
+// strings
+func formatScalar(v interface{}) (string, bool) {
+	switch value := v.(type) {
+	case time.Time:
+		return value.Format(time.RFC3339Nano), true
+	case *time.Time:
+		if value == nil {
+			return "", false
+		}
+		return value.Format(time.RFC3339Nano), true
+	case fmt.Stringer:
+		return value.String(), true
+	}
+	rv := reflect.ValueOf(v)
+	switch {
+	case !rv.IsValid():
+		return "", false
+	case rv.Kind() == reflect.Bool:
+		return strconv.FormatBool(rv.Bool()), true
+	case isIntKind(rv.Kind()):
+		return strconv.FormatInt(rv.Int(), 10), true
+	case isUintKind(rv.Kind()):
+		return strconv.FormatUint(rv.Uint(), 10), true
+	case rv.Kind() == reflect.Float32:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 32), true
+	case rv.Kind() == reflect.Float64:
+		return strconv.FormatFloat(rv.Float(), 'f', -1, 64), true
+	case rv.Kind() == reflect.String:
+		return rv.String(), true
+	}
+	return "", false
+}
+
 // ConvertStringMap converts a map[string]string to a map[string]interface{}.
 //
 // This is done mostly in order to conform to the gqlgen Graphql Map scalar.
+//
+// Deprecated: use ConvertMapValues.
 func ConvertStringMap(inp map[string]string) map[string]interface{} {
-	out := make(map[string]interface{})
-	if inp == nil {
-		return out
-	}
-	for k, v := range inp {
-		val := interface{}(v)
-		out[k] = val
-	}
+	out, _ := ConvertMapValues(inp, func(v string) (interface{}, error) {
+		return v, nil
+	})
 	return out
 }
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/savannahghi/converterandformatter"
 	"github.com/stretchr/testify/assert"
@@ -202,8 +203,19 @@ func Test_convertInterfaceMap(t *testing.T) {
 					"a": 1,
 				},
 			},
+			want: map[string]string{},
+		},
+		{
+			name: "mixed value types",
+			args: args{
+				inp: map[string]interface{}{
+					"a": "1",
+					"b": 2,
+					"c": nil,
+				},
+			},
 			want: map[string]string{
-				"a": "invalid string value: 1",
+				"a": "1",
 			},
 		},
 	}
@@ -311,3 +323,90 @@ func Test_convertStringMap(t *testing.T) {
 		})
 	}
 }
+
+func TestToStringMap(t *testing.T) {
+	when := time.Date(2021, 7, 1, 9, 30, 0, 0, time.UTC)
+	in := map[string]interface{}{
+		"name":    "Wanjiku",
+		"age":     31,
+		"height":  1.65,
+		"active":  true,
+		"joined":  when,
+		"channel": converterandformatter.OptInChannelSMS,
+		"tags":    []string{"a"},
+		"nothing": nil,
+	}
+
+	tests := []struct {
+		name      string
+		mode      converterandformatter.StringMapMode
+		want      map[string]string
+		wantPaths []string
+	}{
+		{
+			name:      "strict",
+			mode:      converterandformatter.StringMapStrict,
+			want:      nil,
+			wantPaths: []string{"active", "age", "channel", "height", "joined", "nothing", "tags"},
+		},
+		{
+			name: "skip",
+			mode: converterandformatter.StringMapSkip,
+			want: map[string]string{"name": "Wanjiku"},
+		},
+		{
+			name:      "lenient",
+			mode:      converterandformatter.StringMapLenient,
+			want:      nil,
+			wantPaths: []string{"nothing", "tags"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := converterandformatter.ToStringMap(in, tt.mode)
+			assert.Equal(t, tt.want, got)
+			if len(tt.wantPaths) == 0 {
+				assert.Nil(t, err)
+				return
+			}
+			fieldErrs, ok := err.(converterandformatter.FieldErrors)
+			assert.True(t, ok)
+			paths := []string{}
+			for _, fe := range fieldErrs {
+				paths = append(paths, fe.Path)
+			}
+			assert.Equal(t, tt.wantPaths, paths)
+		})
+	}
+}
+
+func TestToStringMap_Lenient(t *testing.T) {
+	got, err := converterandformatter.ToStringMap(map[string]interface{}{
+		"age":     31,
+		"count":   uint8(2),
+		"height":  1.65,
+		"whole":   2.0,
+		"ratio":   float32(0.5),
+		"active":  false,
+		"joined":  time.Date(2021, 7, 1, 9, 30, 0, 0, time.UTC),
+		"channel": converterandformatter.OptInChannelSMS,
+	}, converterandformatter.StringMapLenient)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]string{
+		"age":     "31",
+		"count":   "2",
+		"height":  "1.65",
+		"whole":   "2",
+		"ratio":   "0.5",
+		"active":  "false",
+		"joined":  "2021-07-01T09:30:00Z",
+		"channel": "SMS",
+	}, got)
+}
+
+func TestGenerateRandomEmail_Unique(t *testing.T) {
+	first := converterandformatter.GenerateRandomEmail()
+	second := converterandformatter.GenerateRandomEmail()
+	assert.NotEqual(t, first, second)
+	assert.Regexp(t, `^be\.well\+[0-9a-f]{32}@bewell\.co\.ke$`, first)
+}
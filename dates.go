@@ -0,0 +1,474 @@
+package converterandformatter
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// nairobiLocation is East Africa Time, the time zone that dates without one
+// are read in and that dates are displayed in
+var nairobiLocation = loadNairobiLocation()
+
+func loadNairobiLocation() *time.Location {
+	location, err := time.LoadLocation("Africa/Nairobi")
+	if err != nil {
+		// East Africa Time has no daylight saving
+		return time.FixedZone("EAT", 3*60*60)
+	}
+	return location
+}
+
+// NairobiLocation returns the Africa/Nairobi time zone
+func NairobiLocation() *time.Location {
+	return nairobiLocation
+}
+
+// ToNairobiTime returns the same instant in Africa/Nairobi time
+func ToNairobiTime(t time.Time) time.Time {
+	return t.In(nairobiLocation)
+}
+
+// errors returned, wrapped in a DateError, by ParseFlexibleDate. Use
+// errors.Is to tell them apart.
+var (
+	ErrDateEmpty     = errors.New("date is empty")
+	ErrDateInvalid   = errors.New("date is not valid")
+	ErrDateAmbiguous = errors.New("date could be day first or month first")
+)
+
+// DateError is returned when a date cannot be parsed
+type DateError struct {
+	Value string
+	Err   error
+}
+
+func (e *DateError) Error() string {
+	return fmt.Sprintf("%v: %s", e.Err, e.Value)
+}
+
+// Unwrap returns the reason the date could not be parsed e.g
+// ErrDateAmbiguous
+func (e *DateError) Unwrap() error {
+	return e.Err
+}
+
+// DateParseOption changes how ParseFlexibleDate reads a date
+type DateParseOption func(*dateParseConfig)
+
+type dateParseConfig struct {
+	monthFirst      bool
+	rejectAmbiguous bool
+	twoDigitPast    bool
+	location        *time.Location
+}
+
+// PreferMonthFirst reads ambiguous numeric dates as mm/dd/yyyy instead of
+// the East African dd/mm/yyyy
+func PreferMonthFirst() DateParseOption {
+	return func(c *dateParseConfig) {
+		c.monthFirst = true
+	}
+}
+
+// RejectAmbiguousDates fails with ErrDateAmbiguous when a numeric date such
+// as 03/04/2021 could be read either day first or month first
+func RejectAmbiguousDates() DateParseOption {
+	return func(c *dateParseConfig) {
+		c.rejectAmbiguous = true
+	}
+}
+
+// WithTwoDigitYearPast reads two digit years as the most recent year that is
+// not in the future e.g for dates of birth. Otherwise they are read as a year
+// in the century that ends 20 years after the current year.
+func WithTwoDigitYearPast() DateParseOption {
+	return func(c *dateParseConfig) {
+		c.twoDigitPast = true
+	}
+}
+
+// WithDateLocation reads dates that do not state a time zone in the supplied
+// location instead of Africa/Nairobi
+func WithDateLocation(location *time.Location) DateParseOption {
+	return func(c *dateParseConfig) {
+		c.location = location
+	}
+}
+
+// numericDatePattern matches dates such as 31/12/2021, 12-31-21, 2021.12.31
+// and, optionally, a time of day after them
+var numericDatePattern = regexp.MustCompile(
+	`^([0-9]{1,4})[/.\-]([0-9]{1,2})[/.\-]([0-9]{1,4})(?:[ T](.+))?$`)
+
+// textDateLayouts are the layouts, other than numeric ones, that
+// ParseFlexibleDate tries in order
+var textDateLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2 January 2006",
+	"2 Jan 2006",
+	"January 2, 2006",
+	"Jan 2, 2006",
+	"January 2 2006",
+	"Jan 2 2006",
+	"Monday, 2 January 2006",
+	"Mon, 2 Jan 2006",
+	"02-Jan-2006",
+	"2-Jan-06",
+	"20060102",
+}
+
+// clockLayouts are the times of day accepted after a numeric date
+var clockLayouts = []string{"15:04", "15:04:05", "3:04 PM", "3:04PM", "3PM", "3 PM"}
+
+// ParseFlexibleDate reads a date written in any of the common numeric
+// (31/12/2021, 2021-12-31, 31.12.21) or text (31 December 2021, Dec 31,
+// 2021) formats, optionally followed by a time of day.
+//
+// Numeric dates are read day first unless PreferMonthFirst is used, except
+// where only one reading is possible: 12/31/2021 is always 31 December.
+// Use ParseFlexibleDateWithAmbiguity to find out whether the preference was
+// needed. Dates without a time zone are read in Africa/Nairobi time.
+//
+// The returned error is a *DateError that wraps one of the ErrDate errors.
+func ParseFlexibleDate(value string, opts ...DateParseOption) (time.Time, error) {
+	date, _, err := ParseFlexibleDateWithAmbiguity(value, opts...)
+	return date, err
+}
+
+// ParseFlexibleDateWithAmbiguity reads a date like ParseFlexibleDate and
+// also reports whether it was ambiguous i.e a numeric date such as
+// 03/04/2021 that could have been read either day first or month first. The
+// date returned for an ambiguous date follows PreferMonthFirst.
+func ParseFlexibleDateWithAmbiguity(
+	value string, opts ...DateParseOption) (time.Time, bool, error) {
+	config := dateParseConfig{location: nairobiLocation}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	fail := func(err error) (time.Time, bool, error) {
+		return time.Time{}, false, &DateError{Value: value, Err: err}
+	}
+
+	text := strings.Join(strings.Fields(value), " ")
+	if text == "" {
+		return fail(ErrDateEmpty)
+	}
+
+	if m := numericDatePattern.FindStringSubmatch(text); m != nil {
+		year, month, day, ambiguous, err := numericDateParts(
+			m[1], m[2], m[3], time.Now().In(config.location).Year(), config)
+		if err != nil {
+			return fail(err)
+		}
+		date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, config.location)
+		if date.Day() != day || int(date.Month()) != month {
+			// e.g 31/02/2021, which time.Date would roll over into March
+			return fail(ErrDateInvalid)
+		}
+		if m[4] == "" {
+			return date, ambiguous, nil
+		}
+		for _, layout := range clockLayouts {
+			clock, err := time.Parse(layout, strings.ToUpper(m[4]))
+			if err == nil {
+				// the wall clock time, which is not always the same as the
+				// time elapsed since midnight on daylight saving days
+				date = time.Date(year, time.Month(month), day,
+					clock.Hour(), clock.Minute(), clock.Second(), 0, config.location)
+				return date, ambiguous, nil
+			}
+		}
+		// times with a zone or fractional seconds e.g RFC 3339 timestamps
+		// are read by the text layouts
+	}
+
+	for _, layout := range textDateLayouts {
+		date, err := time.ParseInLocation(layout, text, config.location)
+		if err == nil {
+			return date, false, nil
+		}
+	}
+	return fail(ErrDateInvalid)
+}
+
+// numericDateParts works out which of the three numbers in a numeric date is
+// the year, month and day. Two digit years are read relative to the supplied
+// current year. The date is ambiguous if the day and month could be swapped.
+func numericDateParts(
+	first, second, third string, currentYear int,
+	config dateParseConfig) (int, int, int, bool, error) {
+	a, _ := strconv.Atoi(first)
+	b, _ := strconv.Atoi(second)
+	c, _ := strconv.Atoi(third)
+
+	if len(first) == 4 {
+		// yyyy-mm-dd is never written yyyy-dd-mm
+		return a, b, c, false, nil
+	}
+	if len(third) != 2 && len(third) != 4 {
+		return 0, 0, 0, false, ErrDateInvalid
+	}
+	year := c
+	if len(third) == 2 {
+		year = twoDigitYear(c, currentYear, config.twoDigitPast)
+	}
+
+	switch {
+	case a > 12 && b > 12:
+		return 0, 0, 0, false, ErrDateInvalid
+	case a > 12:
+		return year, b, a, false, nil
+	case b > 12:
+		return year, a, b, false, nil
+	case a == b:
+		return year, a, b, false, nil
+	case config.rejectAmbiguous:
+		return 0, 0, 0, false, ErrDateAmbiguous
+	case config.monthFirst:
+		return year, a, b, true, nil
+	default:
+		return year, b, a, true, nil
+	}
+}
+
+// twoDigitYear expands a two digit year to the matching year in the century
+// that ends 20 years after the current year or, for past only dates, in the
+// century that ends with the current year
+func twoDigitYear(yy, currentYear int, pastOnly bool) int {
+	latest := currentYear + 20
+	if pastOnly {
+		latest = currentYear
+	}
+	year := latest - latest%100 + yy
+	if year > latest {
+		year -= 100
+	}
+	return year
+}
+
+// DateStyle is a named way of writing a date
+type DateStyle string
+
+// the styles that FormatDate can write dates in
+const (
+	// DateStyleShort e.g 05/03/2021
+	DateStyleShort DateStyle = "SHORT"
+	// DateStyleMedium e.g 5 Mar 2021
+	DateStyleMedium DateStyle = "MEDIUM"
+	// DateStyleLong e.g 5 March 2021
+	DateStyleLong DateStyle = "LONG"
+	// DateStyleFull e.g Friday, 5 March 2021
+	DateStyleFull DateStyle = "FULL"
+	// DateStyleISO e.g 2021-03-05
+	DateStyleISO DateStyle = "ISO"
+	// DateStyleDateTime e.g 05/03/2021 14:30
+	DateStyleDateTime DateStyle = "DATE_TIME"
+)
+
+// AllDateStyles is a list of all the known date styles
+var AllDateStyles = []DateStyle{
+	DateStyleShort,
+	DateStyleMedium,
+	DateStyleLong,
+	DateStyleFull,
+	DateStyleISO,
+	DateStyleDateTime,
+}
+
+// IsValid returns true if the date style is known
+func (s DateStyle) IsValid() bool {
+	switch s {
+	case DateStyleShort, DateStyleMedium, DateStyleLong, DateStyleFull,
+		DateStyleISO, DateStyleDateTime:
+		return true
+	}
+	return false
+}
+
+func (s DateStyle) String() string {
+	return string(s)
+}
+
+var swahiliMonths = []string{
+	"Januari", "Februari", "Machi", "Aprili", "Mei", "Juni", "Julai",
+	"Agosti", "Septemba", "Oktoba", "Novemba", "Desemba",
+}
+
+var swahiliShortMonths = []string{
+	"Jan", "Feb", "Mac", "Apr", "Mei", "Jun", "Jul", "Ago", "Sep", "Okt",
+	"Nov", "Des",
+}
+
+// Swahili weekdays, starting from Sunday to match time.Weekday
+var swahiliWeekdays = []string{
+	"Jumapili", "Jumatatu", "Jumanne", "Jumatano", "Alhamisi", "Ijumaa",
+	"Jumamosi",
+}
+
+// FormatDate writes the date of an instant, as it is in Africa/Nairobi, in a
+// named style. Month and day names are written in the supplied language.
+func FormatDate(t time.Time, style DateStyle, lang Language) (string, error) {
+	if !lang.IsValid() {
+		return "", fmt.Errorf("unknown language: %s", lang)
+	}
+	t = ToNairobiTime(t)
+
+	switch style {
+	case DateStyleShort:
+		return t.Format("02/01/2006"), nil
+	case DateStyleISO:
+		return t.Format("2006-01-02"), nil
+	case DateStyleDateTime:
+		return t.Format("02/01/2006 15:04"), nil
+	}
+
+	if lang == LanguageSwahili {
+		month := swahiliMonths[t.Month()-1]
+		switch style {
+		case DateStyleMedium:
+			return fmt.Sprintf("%d %s %d", t.Day(), swahiliShortMonths[t.Month()-1], t.Year()), nil
+		case DateStyleLong:
+			return fmt.Sprintf("%d %s %d", t.Day(), month, t.Year()), nil
+		case DateStyleFull:
+			return fmt.Sprintf(
+				"%s, %d %s %d", swahiliWeekdays[t.Weekday()], t.Day(), month, t.Year()), nil
+		}
+	} else {
+		switch style {
+		case DateStyleMedium:
+			return t.Format("2 Jan 2006"), nil
+		case DateStyleLong:
+			return t.Format("2 January 2006"), nil
+		case DateStyleFull:
+			return t.Format("Monday, 2 January 2006"), nil
+		}
+	}
+	return "", fmt.Errorf("unknown date style: %s", style)
+}
+
+// Age returns how old, in completed years, someone born on the date of birth
+// is today in Africa/Nairobi
+func Age(dateOfBirth time.Time) (int, error) {
+	return AgeAt(dateOfBirth, time.Now())
+}
+
+// AgeAt returns how old, in completed years, someone born on the date of
+// birth was at an instant. Both dates are compared as calendar dates in
+// Africa/Nairobi; people born on 29 February turn a year older on 1 March in
+// non leap years.
+func AgeAt(dateOfBirth, at time.Time) (int, error) {
+	dob, on := ToNairobiTime(dateOfBirth), ToNairobiTime(at)
+	if dob.After(on) {
+		return 0, fmt.Errorf(
+			"the date of birth %s is after %s",
+			dob.Format("2006-01-02"), on.Format("2006-01-02"))
+	}
+	age := on.Year() - dob.Year()
+	if on.Month() < dob.Month() ||
+		(on.Month() == dob.Month() && on.Day() < dob.Day()) {
+		age--
+	}
+	return age, nil
+}
+
+// relativeUnit is a unit of time in a humanized relative time
+type relativeUnit struct {
+	english        string
+	englishArticle string
+
+	// the Swahili singular and plural nouns, the form of "one" that agrees
+	// with them, and the "-liyopita" (that passed) forms that agree with
+	// them in the singular and plural
+	swahili           string
+	swahiliPlural     string
+	swahiliOne        string
+	swahiliPast       string
+	swahiliPastPlural string
+}
+
+var (
+	relativeMinute = relativeUnit{"minute", "a", "dakika", "dakika", "moja", "iliyopita", "zilizopita"}
+	relativeHour   = relativeUnit{"hour", "an", "saa", "saa", "moja", "iliyopita", "zilizopita"}
+	relativeDay    = relativeUnit{"day", "a", "siku", "siku", "moja", "iliyopita", "zilizopita"}
+	relativeWeek   = relativeUnit{"week", "a", "wiki", "wiki", "moja", "iliyopita", "zilizopita"}
+	relativeMonth  = relativeUnit{"month", "a", "mwezi", "miezi", "mmoja", "uliopita", "iliyopita"}
+	relativeYear   = relativeUnit{"year", "a", "mwaka", "miaka", "mmoja", "uliopita", "iliyopita"}
+)
+
+// HumanizeTime describes when an instant was, or will be, relative to now
+// e.g "5 minutes ago", "yesterday", "in 2 weeks" or, in Swahili, "dakika 5
+// zilizopita", "jana", "baada ya wiki 2"
+func HumanizeTime(t, now time.Time, lang Language) (string, error) {
+	if !lang.IsValid() {
+		return "", fmt.Errorf("unknown language: %s", lang)
+	}
+	swahili := lang == LanguageSwahili
+	elapsed := now.Sub(t)
+	future := elapsed < 0
+	if future {
+		elapsed = -elapsed
+	}
+	days := int(elapsed.Hours()/24 + 0.5)
+
+	var unit relativeUnit
+	var n int
+	switch {
+	case elapsed < 45*time.Second:
+		if swahili {
+			return "sasa hivi", nil
+		}
+		return "just now", nil
+	case elapsed < 45*time.Minute:
+		unit, n = relativeMinute, int(elapsed.Minutes()+0.5)
+	case elapsed < 22*time.Hour:
+		unit, n = relativeHour, int(elapsed.Hours()+0.5)
+	case elapsed < 36*time.Hour:
+		switch {
+		case swahili && future:
+			return "kesho", nil
+		case swahili:
+			return "jana", nil
+		case future:
+			return "tomorrow", nil
+		default:
+			return "yesterday", nil
+		}
+	case days < 7:
+		unit, n = relativeDay, days
+	case days < 30:
+		unit, n = relativeWeek, days/7
+	case days < 365:
+		unit, n = relativeMonth, days/30
+	default:
+		unit, n = relativeYear, days/365
+	}
+	if n < 1 {
+		n = 1
+	}
+
+	if swahili {
+		amount := fmt.Sprintf("%s %d", unit.swahiliPlural, n)
+		past := unit.swahiliPastPlural
+		if n == 1 {
+			amount, past = unit.swahili+" "+unit.swahiliOne, unit.swahiliPast
+		}
+		if future {
+			return "baada ya " + amount, nil
+		}
+		return amount + " " + past, nil
+	}
+
+	amount := fmt.Sprintf("%d %ss", n, unit.english)
+	if n == 1 {
+		amount = unit.englishArticle + " " + unit.english
+	}
+	if future {
+		return "in " + amount, nil
+	}
+	return amount + " ago", nil
+}
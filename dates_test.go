@@ -0,0 +1,353 @@
+package converterandformatter_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/savannahghi/converterandformatter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFlexibleDate(t *testing.T) {
+	nairobi := converterandformatter.NairobiLocation()
+	tests := []struct {
+		name    string
+		value   string
+		opts    []converterandformatter.DateParseOption
+		want    time.Time
+		wantErr error
+	}{
+		{
+			name:  "day first",
+			value: "05/03/2021",
+			want:  time.Date(2021, 3, 5, 0, 0, 0, 0, nairobi),
+		},
+		{
+			name:  "month first preferred",
+			value: "05/03/2021",
+			opts:  []converterandformatter.DateParseOption{converterandformatter.PreferMonthFirst()},
+			want:  time.Date(2021, 5, 3, 0, 0, 0, 0, nairobi),
+		},
+		{
+			name:  "unambiguously month first",
+			value: "12/31/2021",
+			want:  time.Date(2021, 12, 31, 0, 0, 0, 0, nairobi),
+		},
+		{
+			name:  "unambiguously day first",
+			value: "31-12-2021",
+			opts:  []converterandformatter.DateParseOption{converterandformatter.PreferMonthFirst()},
+			want:  time.Date(2021, 12, 31, 0, 0, 0, 0, nairobi),
+		},
+		{
+			name:  "same day and month is not ambiguous",
+			value: "4.4.21",
+			opts:  []converterandformatter.DateParseOption{converterandformatter.RejectAmbiguousDates()},
+			want:  time.Date(2021, 4, 4, 0, 0, 0, 0, nairobi),
+		},
+		{
+			name:  "two digit year in the 1900s",
+			value: "1/2/85",
+			want:  time.Date(1985, 2, 1, 0, 0, 0, 0, nairobi),
+		},
+		{
+			name:  "ISO",
+			value: "2021-03-05",
+			want:  time.Date(2021, 3, 5, 0, 0, 0, 0, nairobi),
+		},
+		{
+			name:  "with a 24 hour time",
+			value: "05/03/2021 14:30",
+			want:  time.Date(2021, 3, 5, 14, 30, 0, 0, nairobi),
+		},
+		{
+			name:  "with a 12 hour time",
+			value: "05/03/2021 2:30 pm",
+			want:  time.Date(2021, 3, 5, 14, 30, 0, 0, nairobi),
+		},
+		{
+			name:  "RFC 3339",
+			value: "2021-03-05T11:30:00Z",
+			want:  time.Date(2021, 3, 5, 11, 30, 0, 0, time.UTC),
+		},
+		{
+			name:  "long",
+			value: "5 March 2021",
+			want:  time.Date(2021, 3, 5, 0, 0, 0, 0, nairobi),
+		},
+		{
+			name:  "American text",
+			value: "Mar 5, 2021",
+			want:  time.Date(2021, 3, 5, 0, 0, 0, 0, nairobi),
+		},
+		{
+			name:  "other location",
+			value: "05/03/2021",
+			opts:  []converterandformatter.DateParseOption{converterandformatter.WithDateLocation(time.UTC)},
+			want:  time.Date(2021, 3, 5, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "ambiguous",
+			value:   "05/03/2021",
+			opts:    []converterandformatter.DateParseOption{converterandformatter.RejectAmbiguousDates()},
+			wantErr: converterandformatter.ErrDateAmbiguous,
+		},
+		{
+			name:    "no such day",
+			value:   "31/02/2021",
+			wantErr: converterandformatter.ErrDateInvalid,
+		},
+		{
+			name:    "neither number is a month",
+			value:   "13/13/2021",
+			wantErr: converterandformatter.ErrDateInvalid,
+		},
+		{
+			name:    "three digit year",
+			value:   "1/2/202",
+			wantErr: converterandformatter.ErrDateInvalid,
+		},
+		{
+			name:    "bad time",
+			value:   "05/03/2021 25:00",
+			wantErr: converterandformatter.ErrDateInvalid,
+		},
+		{
+			name:    "not a date",
+			value:   "tomorrow",
+			wantErr: converterandformatter.ErrDateInvalid,
+		},
+		{
+			name:    "empty",
+			value:   " ",
+			wantErr: converterandformatter.ErrDateEmpty,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := converterandformatter.ParseFlexibleDate(tt.value, tt.opts...)
+			if tt.wantErr != nil {
+				assert.True(t, errors.Is(err, tt.wantErr), err)
+				var dateErr *converterandformatter.DateError
+				assert.True(t, errors.As(err, &dateErr))
+				return
+			}
+			assert.Nil(t, err)
+			assert.True(t, tt.want.Equal(got), got)
+		})
+	}
+}
+
+func TestParseFlexibleDate_TwoDigitYears(t *testing.T) {
+	nairobi := converterandformatter.NairobiLocation()
+	thisYear := time.Now().In(nairobi).Year()
+	nextYear := fmt.Sprintf("1/2/%02d", (thisYear+1)%100)
+
+	got, err := converterandformatter.ParseFlexibleDate(nextYear)
+	assert.Nil(t, err)
+	assert.Equal(t, thisYear+1, got.Year())
+
+	got, err = converterandformatter.ParseFlexibleDate(
+		nextYear, converterandformatter.WithTwoDigitYearPast())
+	assert.Nil(t, err)
+	assert.Equal(t, thisYear-99, got.Year())
+
+	got, err = converterandformatter.ParseFlexibleDate(
+		fmt.Sprintf("1/2/%02d", thisYear%100), converterandformatter.WithTwoDigitYearPast())
+	assert.Nil(t, err)
+	assert.Equal(t, thisYear, got.Year())
+
+	got, err = converterandformatter.ParseFlexibleDate(fmt.Sprintf("1/2/%02d", (thisYear+21)%100))
+	assert.Nil(t, err)
+	assert.Equal(t, thisYear-79, got.Year())
+}
+
+func TestParseFlexibleDate_DaylightSaving(t *testing.T) {
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skipf("time zone data is not available: %v", err)
+	}
+	// clocks went forward an hour at 2am on 14 March 2021
+	got, err := converterandformatter.ParseFlexibleDate(
+		"14/03/2021 14:30", converterandformatter.WithDateLocation(newYork))
+	assert.Nil(t, err)
+	assert.True(t, time.Date(2021, 3, 14, 14, 30, 0, 0, newYork).Equal(got), got)
+}
+
+func TestParseFlexibleDateWithAmbiguity(t *testing.T) {
+	tests := []struct {
+		value     string
+		ambiguous bool
+	}{
+		{"05/03/2021", true},
+		{"05/03/2021 14:30", true},
+		{"31/12/2021", false},
+		{"4/4/2021", false},
+		{"2021-03-05", false},
+		{"5 March 2021", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			_, ambiguous, err := converterandformatter.ParseFlexibleDateWithAmbiguity(tt.value)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.ambiguous, ambiguous)
+		})
+	}
+
+	_, ambiguous, err := converterandformatter.ParseFlexibleDateWithAmbiguity("31/02/2021")
+	assert.NotNil(t, err)
+	assert.False(t, ambiguous)
+}
+
+func TestFormatDate(t *testing.T) {
+	// 21:30 UTC is already the next day in Nairobi
+	when := time.Date(2021, 3, 4, 21, 30, 0, 0, time.UTC)
+	tests := []struct {
+		style   converterandformatter.DateStyle
+		english string
+		swahili string
+	}{
+		{converterandformatter.DateStyleShort, "05/03/2021", "05/03/2021"},
+		{converterandformatter.DateStyleMedium, "5 Mar 2021", "5 Mac 2021"},
+		{converterandformatter.DateStyleLong, "5 March 2021", "5 Machi 2021"},
+		{converterandformatter.DateStyleFull, "Friday, 5 March 2021", "Ijumaa, 5 Machi 2021"},
+		{converterandformatter.DateStyleISO, "2021-03-05", "2021-03-05"},
+		{converterandformatter.DateStyleDateTime, "05/03/2021 00:30", "05/03/2021 00:30"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.style.String(), func(t *testing.T) {
+			assert.True(t, tt.style.IsValid())
+			got, err := converterandformatter.FormatDate(when, tt.style, converterandformatter.LanguageEnglish)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.english, got)
+
+			got, err = converterandformatter.FormatDate(when, tt.style, converterandformatter.LanguageSwahili)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.swahili, got)
+		})
+	}
+	assert.Len(t, converterandformatter.AllDateStyles, len(tests))
+
+	_, err := converterandformatter.FormatDate(when, "OTHER", converterandformatter.LanguageEnglish)
+	assert.NotNil(t, err)
+	_, err = converterandformatter.FormatDate(when, converterandformatter.DateStyleShort, "fr")
+	assert.NotNil(t, err)
+}
+
+func TestAgeAt(t *testing.T) {
+	nairobi := converterandformatter.NairobiLocation()
+	tests := []struct {
+		name    string
+		dob     time.Time
+		at      time.Time
+		want    int
+		wantErr bool
+	}{
+		{
+			name: "before the birthday",
+			dob:  time.Date(1990, 6, 15, 0, 0, 0, 0, nairobi),
+			at:   time.Date(2021, 6, 14, 23, 0, 0, 0, nairobi),
+			want: 30,
+		},
+		{
+			name: "on the birthday",
+			dob:  time.Date(1990, 6, 15, 0, 0, 0, 0, nairobi),
+			at:   time.Date(2021, 6, 15, 0, 0, 0, 0, nairobi),
+			want: 31,
+		},
+		{
+			name: "birthday in Nairobi but not yet in UTC",
+			dob:  time.Date(1990, 6, 15, 0, 0, 0, 0, nairobi),
+			at:   time.Date(2021, 6, 14, 22, 0, 0, 0, time.UTC),
+			want: 31,
+		},
+		{
+			name: "leap day birthday in a common year",
+			dob:  time.Date(2000, 2, 29, 0, 0, 0, 0, nairobi),
+			at:   time.Date(2021, 2, 28, 0, 0, 0, 0, nairobi),
+			want: 20,
+		},
+		{
+			name: "leap day birthday celebrated on 1 March",
+			dob:  time.Date(2000, 2, 29, 0, 0, 0, 0, nairobi),
+			at:   time.Date(2021, 3, 1, 0, 0, 0, 0, nairobi),
+			want: 21,
+		},
+		{
+			name: "newborn",
+			dob:  time.Date(2021, 6, 15, 0, 0, 0, 0, nairobi),
+			at:   time.Date(2021, 6, 15, 0, 0, 0, 0, nairobi),
+			want: 0,
+		},
+		{
+			name:    "born in the future",
+			dob:     time.Date(2022, 1, 1, 0, 0, 0, 0, nairobi),
+			at:      time.Date(2021, 6, 15, 0, 0, 0, 0, nairobi),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := converterandformatter.AgeAt(tt.dob, tt.at)
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+
+	age, err := converterandformatter.Age(time.Now().AddDate(-20, 0, -1))
+	assert.Nil(t, err)
+	assert.Equal(t, 20, age)
+}
+
+func TestHumanizeTime(t *testing.T) {
+	now := time.Date(2021, 6, 15, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name    string
+		offset  time.Duration
+		english string
+		swahili string
+	}{
+		{"just now", -10 * time.Second, "just now", "sasa hivi"},
+		{"a minute ago", -time.Minute, "a minute ago", "dakika moja iliyopita"},
+		{"minutes ago", -5 * time.Minute, "5 minutes ago", "dakika 5 zilizopita"},
+		{"an hour ago", -time.Hour, "an hour ago", "saa moja iliyopita"},
+		{"hours ago", -3 * time.Hour, "3 hours ago", "saa 3 zilizopita"},
+		{"yesterday", -26 * time.Hour, "yesterday", "jana"},
+		{"days ago", -2 * 24 * time.Hour, "2 days ago", "siku 2 zilizopita"},
+		{"a week ago", -8 * 24 * time.Hour, "a week ago", "wiki moja iliyopita"},
+		{"a month ago", -31 * 24 * time.Hour, "a month ago", "mwezi mmoja uliopita"},
+		{"months ago", -150 * 24 * time.Hour, "5 months ago", "miezi 5 iliyopita"},
+		{"a year ago", -400 * 24 * time.Hour, "a year ago", "mwaka mmoja uliopita"},
+		{"years ago", -800 * 24 * time.Hour, "2 years ago", "miaka 2 iliyopita"},
+		{"in minutes", 10 * time.Minute, "in 10 minutes", "baada ya dakika 10"},
+		{"tomorrow", 24 * time.Hour, "tomorrow", "kesho"},
+		{"in weeks", 15 * 24 * time.Hour, "in 2 weeks", "baada ya wiki 2"},
+		{"in a year", 365 * 24 * time.Hour, "in a year", "baada ya mwaka mmoja"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			when := now.Add(tt.offset)
+			got, err := converterandformatter.HumanizeTime(when, now, converterandformatter.LanguageEnglish)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.english, got)
+
+			got, err = converterandformatter.HumanizeTime(when, now, converterandformatter.LanguageSwahili)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.swahili, got)
+		})
+	}
+
+	_, err := converterandformatter.HumanizeTime(now, now, "fr")
+	assert.NotNil(t, err)
+}
+
+func TestToNairobiTime(t *testing.T) {
+	got := converterandformatter.ToNairobiTime(time.Date(2021, 6, 15, 21, 0, 0, 0, time.UTC))
+	assert.Equal(t, "2021-06-16T00:00:00+03:00", got.Format(time.RFC3339))
+}
@@ -0,0 +1,456 @@
+package converterandformatter
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// FieldError is a conversion error at a path within a value e.g
+// "address.phones[2].number"
+type FieldError struct {
+	Path string
+	Err  error
+}
+
+func (e *FieldError) Error() string {
+	if e.Path == "" {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s: %v", e.Path, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+// FieldErrors collects every field error found in a conversion
+type FieldErrors []*FieldError
+
+func (e FieldErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, err := range e {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// FirestoreValueLoader is implemented by types that MapToStruct reads from a
+// different value e.g Set, which is read from an array. It is the inverse of
+// FirestoreValuer.
+type FirestoreValueLoader interface {
+	LoadFirestoreValue(value interface{}) error
+}
+
+// WithIntegralFloats lets MapToStruct decode a float64 into an integer field
+// when the float has no fractional part, as happens with maps decoded from
+// JSON
+func WithIntegralFloats() StructMapOption {
+	return func(c *structMapConfig) {
+		c.integralFloats = true
+	}
+}
+
+// WithStringNumbers lets MapToStruct parse strings into integer, float and
+// boolean fields
+func WithStringNumbers() StructMapOption {
+	return func(c *structMapConfig) {
+		c.stringNumbers = true
+	}
+}
+
+// WithStringTimes lets MapToStruct parse strings into time.Time fields using
+// the supplied layouts in order. RFC 3339 is used when no layout is given.
+func WithStringTimes(layouts ...string) StructMapOption {
+	return func(c *structMapConfig) {
+		c.stringTimes = true
+		if len(layouts) > 0 {
+			c.timeLayouts = layouts
+		}
+	}
+}
+
+// WithWeakTyping enables all the weak typing options of MapToStruct with
+// their default settings
+func WithWeakTyping() StructMapOption {
+	return func(c *structMapConfig) {
+		c.integralFloats = true
+		c.stringNumbers = true
+		c.stringTimes = true
+	}
+}
+
+// MapToStruct decodes a map, such as a gqlgen Map input or the Data() of a
+// Firestore document, into the struct that target points to. It is the
+// inverse of StructToTypedMap and honors the same struct tags.
+//
+// By default values must already have a compatible type: integers of any
+// size convert to each other and to floats as long as they fit. Weak typing
+// options relax this. Every value that fails to decode is reported in the
+// returned FieldErrors with its full path. A nil map leaves the target as it
+// is.
+func MapToStruct(in map[string]interface{}, target interface{}, opts ...StructMapOption) error {
+	config := newStructMapConfig(opts)
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("expected a non nil pointer to a struct, got %T", target)
+	}
+	if in == nil {
+		return nil
+	}
+	d := decoder{config: config}
+	d.decode("", reflect.ValueOf(in), v.Elem())
+	if len(d.errs) > 0 {
+		return d.errs
+	}
+	return nil
+}
+
+// decodeValue decodes a single value into the variable that target points
+// to using the default MapToStruct rules
+func decodeValue(in interface{}, target interface{}) error {
+	d := decoder{config: newStructMapConfig(nil)}
+	d.decode("", reflect.ValueOf(in), reflect.ValueOf(target).Elem())
+	if len(d.errs) > 0 {
+		return d.errs
+	}
+	return nil
+}
+
+type decoder struct {
+	config structMapConfig
+	errs   FieldErrors
+}
+
+func (d *decoder) fail(path string, format string, args ...interface{}) {
+	d.errs = append(d.errs, &FieldError{Path: path, Err: fmt.Errorf(format, args...)})
+}
+
+// decode sets out from in, recording an error against path on failure
+func (d *decoder) decode(path string, in reflect.Value, out reflect.Value) {
+	for in.IsValid() && in.Kind() == reflect.Interface {
+		in = in.Elem()
+	}
+	if !in.IsValid() || (in.Kind() == reflect.Ptr || in.Kind() == reflect.Map ||
+		in.Kind() == reflect.Slice) && in.IsNil() {
+		out.Set(reflect.Zero(out.Type()))
+		return
+	}
+	if in.Type().AssignableTo(out.Type()) {
+		out.Set(in)
+		return
+	}
+	if out.CanAddr() {
+		if loader, ok := out.Addr().Interface().(FirestoreValueLoader); ok {
+			err := loader.LoadFirestoreValue(in.Interface())
+			if err != nil {
+				d.fail(path, "%v", err)
+			}
+			return
+		}
+	}
+
+	switch {
+	case out.Kind() == reflect.Ptr:
+		elem := reflect.New(out.Type().Elem())
+		d.decode(path, in, elem.Elem())
+		out.Set(elem)
+	case in.Kind() == reflect.Ptr:
+		d.decode(path, in.Elem(), out)
+	case out.Kind() == reflect.Interface:
+		d.fail(path, "%s does not implement %s", in.Type(), out.Type())
+	case out.Type() == timeType:
+		d.decodeTime(path, in, out)
+	case out.Kind() == reflect.Struct:
+		d.decodeStruct(path, in, out)
+	case out.Kind() == reflect.Map:
+		d.decodeMap(path, in, out)
+	case out.Kind() == reflect.Slice, out.Kind() == reflect.Array:
+		d.decodeSlice(path, in, out)
+	case out.Kind() == reflect.String:
+		if in.Kind() != reflect.String {
+			d.fail(path, "expected a string, got %s", in.Type())
+			return
+		}
+		out.SetString(in.String())
+	case out.Kind() == reflect.Bool:
+		d.decodeBool(path, in, out)
+	case isIntKind(out.Kind()), isUintKind(out.Kind()):
+		d.decodeInteger(path, in, out)
+	case isFloatKind(out.Kind()):
+		d.decodeFloat(path, in, out)
+	default:
+		d.fail(path, "can not decode %s into %s", in.Type(), out.Type())
+	}
+}
+
+func (d *decoder) decodeStruct(path string, in reflect.Value, out reflect.Value) {
+	if in.Kind() != reflect.Map || in.Type().Key().Kind() != reflect.String {
+		d.fail(path, "expected a map, got %s", in.Type())
+		return
+	}
+	for _, f := range structFields(out.Type(), d.config.tagKey) {
+		value := in.MapIndex(reflect.ValueOf(f.name).Convert(in.Type().Key()))
+		if !value.IsValid() {
+			continue
+		}
+		field := allocFieldByIndex(out, f.index)
+		fieldPath := joinPath(path, f.name)
+		if f.asString {
+			d.decodeStringOption(fieldPath, value, field)
+			continue
+		}
+		d.decode(fieldPath, value, field)
+	}
+}
+
+func (d *decoder) decodeMap(path string, in reflect.Value, out reflect.Value) {
+	if in.Kind() != reflect.Map {
+		d.fail(path, "expected a map, got %s", in.Type())
+		return
+	}
+	if out.Type().Key().Kind() != reflect.String || in.Type().Key().Kind() != reflect.String {
+		d.fail(path, "can not decode %s into %s", in.Type(), out.Type())
+		return
+	}
+	result := reflect.MakeMapWithSize(out.Type(), in.Len())
+	iter := in.MapRange()
+	for iter.Next() {
+		key := iter.Key().String()
+		elem := reflect.New(out.Type().Elem()).Elem()
+		d.decode(joinPath(path, key), iter.Value(), elem)
+		result.SetMapIndex(reflect.ValueOf(key).Convert(out.Type().Key()), elem)
+	}
+	out.Set(result)
+}
+
+func (d *decoder) decodeSlice(path string, in reflect.Value, out reflect.Value) {
+	if in.Kind() != reflect.Slice && in.Kind() != reflect.Array {
+		d.fail(path, "expected a list, got %s", in.Type())
+		return
+	}
+	if out.Kind() == reflect.Array {
+		if in.Len() != out.Len() {
+			d.fail(path, "expected %d items, got %d", out.Len(), in.Len())
+			return
+		}
+	} else {
+		out.Set(reflect.MakeSlice(out.Type(), in.Len(), in.Len()))
+	}
+	for i := 0; i < in.Len(); i++ {
+		d.decode(fmt.Sprintf("%s[%d]", path, i), in.Index(i), out.Index(i))
+	}
+}
+
+func (d *decoder) decodeTime(path string, in reflect.Value, out reflect.Value) {
+	if in.Kind() != reflect.String || !d.config.stringTimes {
+		d.fail(path, "expected a time, got %s", in.Type())
+		return
+	}
+	for _, layout := range d.config.timeLayouts {
+		t, err := time.Parse(layout, in.String())
+		if err == nil {
+			out.Set(reflect.ValueOf(t))
+			return
+		}
+	}
+	d.fail(path, "unable to parse %q as a time", in.String())
+}
+
+func (d *decoder) decodeBool(path string, in reflect.Value, out reflect.Value) {
+	switch {
+	case in.Kind() == reflect.Bool:
+		out.SetBool(in.Bool())
+	case in.Kind() == reflect.String && d.config.stringNumbers:
+		b, err := strconv.ParseBool(in.String())
+		if err != nil {
+			d.fail(path, "unable to parse %q as a boolean", in.String())
+			return
+		}
+		out.SetBool(b)
+	default:
+		d.fail(path, "expected a boolean, got %s", in.Type())
+	}
+}
+
+func (d *decoder) decodeInteger(path string, in reflect.Value, out reflect.Value) {
+	switch {
+	case isIntKind(in.Kind()):
+		d.setInteger(path, out, in.Int(), 0, false)
+	case isUintKind(in.Kind()):
+		d.setInteger(path, out, 0, in.Uint(), true)
+	case isFloatKind(in.Kind()) && d.config.integralFloats:
+		f := in.Float()
+		if f != math.Trunc(f) || math.IsInf(f, 0) || math.IsNaN(f) {
+			d.fail(path, "%v is not a whole number", f)
+			return
+		}
+		// float64(math.MinInt64) is exact but float64(math.MaxUint64)
+		// rounds up to 2^64, which does not fit
+		if f < math.MinInt64 || f >= math.MaxUint64 {
+			d.fail(path, "%v overflows %s", f, out.Type())
+			return
+		}
+		if f < 0 {
+			d.setInteger(path, out, int64(f), 0, false)
+			return
+		}
+		d.setInteger(path, out, 0, uint64(f), true)
+	case in.Kind() == reflect.String && d.config.stringNumbers:
+		s := strings.TrimSpace(in.String())
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			d.setInteger(path, out, i, 0, false)
+			return
+		}
+		if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+			d.setInteger(path, out, 0, u, true)
+			return
+		}
+		d.fail(path, "unable to parse %q as an integer", in.String())
+	default:
+		d.fail(path, "expected an integer, got %s", in.Type())
+	}
+}
+
+// setInteger stores a signed (or, when unsigned is true, an unsigned)
+// integer in out after checking that it fits
+func (d *decoder) setInteger(path string, out reflect.Value, i int64, u uint64, unsigned bool) {
+	if isIntKind(out.Kind()) {
+		if unsigned {
+			if u > math.MaxInt64 || out.OverflowInt(int64(u)) {
+				d.fail(path, "%d overflows %s", u, out.Type())
+				return
+			}
+			i = int64(u)
+		}
+		if out.OverflowInt(i) {
+			d.fail(path, "%d overflows %s", i, out.Type())
+			return
+		}
+		out.SetInt(i)
+		return
+	}
+	if !unsigned {
+		if i < 0 {
+			d.fail(path, "%d overflows %s", i, out.Type())
+			return
+		}
+		u = uint64(i)
+	}
+	if out.OverflowUint(u) {
+		d.fail(path, "%d overflows %s", u, out.Type())
+		return
+	}
+	out.SetUint(u)
+}
+
+func (d *decoder) decodeFloat(path string, in reflect.Value, out reflect.Value) {
+	var f float64
+	switch {
+	case isFloatKind(in.Kind()):
+		f = in.Float()
+	case isIntKind(in.Kind()):
+		f = float64(in.Int())
+	case isUintKind(in.Kind()):
+		f = float64(in.Uint())
+	case in.Kind() == reflect.String && d.config.stringNumbers:
+		parsed, err := strconv.ParseFloat(strings.TrimSpace(in.String()), 64)
+		if err != nil {
+			d.fail(path, "unable to parse %q as a number", in.String())
+			return
+		}
+		f = parsed
+	default:
+		d.fail(path, "expected a number, got %s", in.Type())
+		return
+	}
+	if out.OverflowFloat(f) {
+		d.fail(path, "%v overflows %s", f, out.Type())
+		return
+	}
+	out.SetFloat(f)
+}
+
+// decodeStringOption decodes a field tagged with the "string" option, whose
+// value was written as a string by StructToTypedMap or encoding/json
+func (d *decoder) decodeStringOption(path string, in reflect.Value, out reflect.Value) {
+	for in.IsValid() && in.Kind() == reflect.Interface {
+		in = in.Elem()
+	}
+	if !in.IsValid() || in.Kind() != reflect.String {
+		d.decode(path, in, out)
+		return
+	}
+	target := out
+	if target.Kind() == reflect.Ptr {
+		target = reflect.New(out.Type().Elem()).Elem()
+	}
+	s := in.String()
+	switch {
+	case target.Kind() == reflect.String:
+		unquoted, err := strconv.Unquote(s)
+		if err != nil {
+			d.fail(path, "expected a quoted string, got %s", s)
+			return
+		}
+		target.SetString(unquoted)
+	default:
+		weak := *d
+		weak.config.stringNumbers = true
+		weak.errs = nil
+		weak.decode(path, in, target)
+		if len(weak.errs) > 0 {
+			d.errs = append(d.errs, weak.errs...)
+			return
+		}
+	}
+	if out.Kind() == reflect.Ptr {
+		out.Set(target.Addr())
+	}
+}
+
+// allocFieldByIndex walks to a possibly promoted field, allocating nil
+// embedded pointers on the way
+func allocFieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func isIntKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
+
+func isUintKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return false
+}
+
+func isFloatKind(k reflect.Kind) bool {
+	return k == reflect.Float32 || k == reflect.Float64
+}
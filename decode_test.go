@@ -0,0 +1,291 @@
+package converterandformatter_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/savannahghi/converterandformatter"
+	"github.com/stretchr/testify/assert"
+)
+
+type Phone struct {
+	Number string `json:"number"`
+	Kind   string `json:"kind,omitempty"`
+}
+
+type Address struct {
+	Town   string  `json:"town"`
+	Phones []Phone `json:"phones"`
+}
+
+type Person struct {
+	Promoted
+	Name     string            `json:"name"`
+	Age      int               `json:"age"`
+	Height   float64           `json:"height"`
+	Active   bool              `json:"active"`
+	Born     time.Time         `json:"born"`
+	Address  *Address          `json:"address"`
+	Tags     []string          `json:"tags"`
+	Labels   map[string]string `json:"labels"`
+	Quoted   int64             `json:"quoted,string"`
+	Extra    interface{}       `json:"extra"`
+	Ignored  string            `json:"-"`
+	Nickname *string           `json:"nickname"`
+}
+
+func TestMapToStruct(t *testing.T) {
+	born := time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC)
+	in := map[string]interface{}{
+		"shared": "promoted",
+		"name":   "Wanjiku",
+		"age":    int64(31),
+		"height": 1.65,
+		"active": true,
+		"born":   born,
+		"address": map[string]interface{}{
+			"town": "Nairobi",
+			"phones": []interface{}{
+				map[string]interface{}{"number": "+254722000000", "kind": "mobile"},
+			},
+		},
+		"tags":     []interface{}{"a", "b"},
+		"labels":   map[string]interface{}{"x": "y"},
+		"quoted":   "42",
+		"extra":    3,
+		"Ignored":  "no",
+		"nickname": "Shiku",
+		"unknown":  "ignored",
+	}
+
+	var got Person
+	err := converterandformatter.MapToStruct(in, &got)
+	assert.Nil(t, err)
+	nickname := "Shiku"
+	assert.Equal(t, Person{
+		Promoted: Promoted{Shared: "promoted"},
+		Name:     "Wanjiku",
+		Age:      31,
+		Height:   1.65,
+		Active:   true,
+		Born:     born,
+		Address: &Address{
+			Town:   "Nairobi",
+			Phones: []Phone{{Number: "+254722000000", Kind: "mobile"}},
+		},
+		Tags:     []string{"a", "b"},
+		Labels:   map[string]string{"x": "y"},
+		Quoted:   42,
+		Extra:    3,
+		Nickname: &nickname,
+	}, got)
+}
+
+func TestMapToStruct_RoundTrip(t *testing.T) {
+	want := SampleStruct{Name: "John Doe", ID: "12121"}
+	m, err := converterandformatter.StructToTypedMap(want)
+	assert.Nil(t, err)
+
+	var got SampleStruct
+	err = converterandformatter.MapToStruct(m, &got)
+	assert.Nil(t, err)
+	assert.Equal(t, want, got)
+}
+
+func TestMapToStruct_WeakTyping(t *testing.T) {
+	in := map[string]interface{}{
+		"age":    float64(31),
+		"height": "1.65",
+		"active": "true",
+		"born":   "1990-05-17",
+	}
+
+	var strict Person
+	err := converterandformatter.MapToStruct(in, &strict)
+	var fieldErrs converterandformatter.FieldErrors
+	assert.True(t, errors.As(err, &fieldErrs))
+	assert.Len(t, fieldErrs, 4)
+
+	var weak Person
+	err = converterandformatter.MapToStruct(
+		in, &weak,
+		converterandformatter.WithIntegralFloats(),
+		converterandformatter.WithStringNumbers(),
+		converterandformatter.WithStringTimes("2006-01-02"),
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, 31, weak.Age)
+	assert.Equal(t, 1.65, weak.Height)
+	assert.True(t, weak.Active)
+	assert.Equal(t, time.Date(1990, 5, 17, 0, 0, 0, 0, time.UTC), weak.Born)
+
+	var all Person
+	err = converterandformatter.MapToStruct(
+		map[string]interface{}{"age": "31", "born": "1990-05-17T00:00:00Z"},
+		&all, converterandformatter.WithWeakTyping())
+	assert.Nil(t, err)
+	assert.Equal(t, 31, all.Age)
+}
+
+func TestMapToStruct_ErrorPaths(t *testing.T) {
+	tests := []struct {
+		name      string
+		in        map[string]interface{}
+		opts      []converterandformatter.StructMapOption
+		wantPaths []string
+	}{
+		{
+			name: "nested list item",
+			in: map[string]interface{}{
+				"address": map[string]interface{}{
+					"phones": []interface{}{
+						map[string]interface{}{"number": "1"},
+						map[string]interface{}{"number": "2"},
+						map[string]interface{}{"number": 3},
+					},
+				},
+			},
+			wantPaths: []string{"address.phones[2].number"},
+		},
+		{
+			name: "fractional float into int",
+			in:   map[string]interface{}{"age": 31.5},
+			opts: []converterandformatter.StructMapOption{
+				converterandformatter.WithIntegralFloats(),
+			},
+			wantPaths: []string{"age"},
+		},
+		{
+			name: "several bad fields",
+			in: map[string]interface{}{
+				"name":   5,
+				"tags":   "not a list",
+				"labels": map[string]interface{}{"x": true},
+				"active": "maybe",
+			},
+			opts: []converterandformatter.StructMapOption{
+				converterandformatter.WithStringNumbers(),
+			},
+			wantPaths: []string{"name", "active", "tags", "labels.x"},
+		},
+		{
+			name: "bad quoted value",
+			in:   map[string]interface{}{"quoted": "forty two"},
+			wantPaths: []string{
+				"quoted",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p Person
+			err := converterandformatter.MapToStruct(tt.in, &p, tt.opts...)
+			var fieldErrs converterandformatter.FieldErrors
+			if !errors.As(err, &fieldErrs) {
+				t.Errorf("MapToStruct() error = %v, want FieldErrors", err)
+				return
+			}
+			paths := []string{}
+			for _, fe := range fieldErrs {
+				paths = append(paths, fe.Path)
+			}
+			assert.ElementsMatch(t, tt.wantPaths, paths)
+			assert.Contains(t, err.Error(), tt.wantPaths[0]+": ")
+		})
+	}
+}
+
+func TestMapToStruct_Overflow(t *testing.T) {
+	type small struct {
+		Tiny  int8    `json:"tiny"`
+		Count uint    `json:"count"`
+		Ratio float32 `json:"ratio"`
+	}
+	var s small
+	err := converterandformatter.MapToStruct(map[string]interface{}{
+		"tiny":  300,
+		"count": -1,
+		"ratio": 1e300,
+	}, &s)
+	var fieldErrs converterandformatter.FieldErrors
+	assert.True(t, errors.As(err, &fieldErrs))
+	assert.Len(t, fieldErrs, 3)
+}
+
+func TestMapToStruct_FloatBounds(t *testing.T) {
+	type whole struct {
+		Signed   int64  `json:"signed"`
+		Unsigned uint64 `json:"unsigned"`
+	}
+	tests := []struct {
+		name    string
+		in      map[string]interface{}
+		want    whole
+		wantErr bool
+	}{
+		{
+			name: "smallest int64",
+			in:   map[string]interface{}{"signed": float64(math.MinInt64)},
+			want: whole{Signed: math.MinInt64},
+		},
+		{
+			name:    "below the smallest int64",
+			in:      map[string]interface{}{"signed": -1e19},
+			wantErr: true,
+		},
+		{
+			name:    "far below the smallest int64",
+			in:      map[string]interface{}{"signed": -1e300},
+			wantErr: true,
+		},
+		{
+			name:    "above the largest uint64",
+			in:      map[string]interface{}{"unsigned": 1e20},
+			wantErr: true,
+		},
+		{
+			name:    "negative into unsigned",
+			in:      map[string]interface{}{"unsigned": -1.0},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got whole
+			err := converterandformatter.MapToStruct(
+				tt.in, &got, converterandformatter.WithIntegralFloats())
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				assert.Equal(t, whole{}, got)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestMapToStruct_NilMap(t *testing.T) {
+	s := SampleStruct{Name: "kept"}
+	assert.Nil(t, converterandformatter.MapToStruct(nil, &s))
+	assert.Equal(t, SampleStruct{Name: "kept"}, s)
+}
+
+func TestMapToStruct_InvalidTarget(t *testing.T) {
+	var s SampleStruct
+	assert.NotNil(t, converterandformatter.MapToStruct(nil, s))
+	assert.NotNil(t, converterandformatter.MapToStruct(nil, (*SampleStruct)(nil)))
+	var i int
+	assert.NotNil(t, converterandformatter.MapToStruct(nil, &i))
+	assert.Nil(t, converterandformatter.MapToStruct(nil, &s))
+}
+
+func TestFieldError(t *testing.T) {
+	inner := errors.New("boom")
+	err := &converterandformatter.FieldError{Path: "a.b", Err: inner}
+	assert.Equal(t, "a.b: boom", err.Error())
+	assert.True(t, errors.Is(err, inner))
+	assert.Equal(t, "boom", (&converterandformatter.FieldError{Err: inner}).Error())
+}
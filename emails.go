@@ -0,0 +1,370 @@
+package converterandformatter
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"unicode/utf8"
+
+	uuid "github.com/kevinburke/go.uuid"
+	"golang.org/x/net/idna"
+)
+
+// errors returned, wrapped in an EmailError, by ValidateEmail and
+// NormalizeEmail. Use errors.Is to tell them apart.
+var (
+	ErrEmailEmpty      = errors.New("email address is empty")
+	ErrEmailSyntax     = errors.New("email address is not valid")
+	ErrEmailLocalPart  = errors.New("email address has an invalid local part")
+	ErrEmailDomain     = errors.New("email address has an invalid domain")
+	ErrEmailDisposable = errors.New("email address uses a disposable email domain")
+)
+
+// the maximum lengths of an email address and of its local part
+const (
+	maxEmailLength     = 254
+	maxEmailLocalPart  = 64
+	maxEmailLabelChars = 63
+)
+
+// emailAtext are the characters, other than letters and digits, allowed in
+// an unquoted local part by RFC 5322
+const emailAtext = "!#$%&'*+-/=?^_`{|}~"
+
+// disposableEmailDomains are well known throwaway mailbox providers
+var disposableEmailDomains = []string{
+	"10minutemail.com",
+	"dispostable.com",
+	"fakeinbox.com",
+	"getnada.com",
+	"guerrillamail.com",
+	"mailinator.com",
+	"maildrop.cc",
+	"sharklasers.com",
+	"temp-mail.org",
+	"throwawaymail.com",
+	"trashmail.com",
+	"yopmail.com",
+}
+
+// gmailDomains are the domains that deliver to the same Gmail mailboxes
+var gmailDomains = []string{"gmail.com", "googlemail.com"}
+
+// EmailError is returned when an email address fails validation
+type EmailError struct {
+	Email string
+	Err   error
+}
+
+func (e *EmailError) Error() string {
+	return fmt.Sprintf("%v: %s", e.Err, e.Email)
+}
+
+// Unwrap returns the reason the email address failed validation e.g
+// ErrEmailDomain
+func (e *EmailError) Unwrap() error {
+	return e.Err
+}
+
+// EmailOption changes how ValidateEmail and NormalizeEmail treat an email
+// address
+type EmailOption func(*emailConfig)
+
+type emailConfig struct {
+	allowIDN               bool
+	blockDisposable        bool
+	extraDisposableDomains []string
+	canonicalizeGmail      bool
+}
+
+// AllowIDN accepts internationalized domain names e.g "mfano.co.ke" written
+// in a non Latin script. NormalizeEmail converts them to punycode.
+func AllowIDN() EmailOption {
+	return func(c *emailConfig) {
+		c.allowIDN = true
+	}
+}
+
+// BlockDisposableDomains rejects addresses at well known disposable email
+// providers, and at any extra domains supplied, with ErrEmailDisposable
+func BlockDisposableDomains(extra ...string) EmailOption {
+	return func(c *emailConfig) {
+		c.blockDisposable = true
+		for _, domain := range extra {
+			c.extraDisposableDomains = append(
+				c.extraDisposableDomains, strings.ToLower(domain))
+		}
+	}
+}
+
+// CanonicalizeGmail makes NormalizeEmail reduce Gmail addresses to the
+// mailbox they deliver to: dots and "+tag" suffixes are removed from the
+// local part and googlemail.com becomes gmail.com
+func CanonicalizeGmail() EmailOption {
+	return func(c *emailConfig) {
+		c.canonicalizeGmail = true
+	}
+}
+
+// IsEmailValid checks that an email address is syntactically valid
+func IsEmailValid(email string, opts ...EmailOption) bool {
+	return ValidateEmail(email, opts...) == nil
+}
+
+// ValidateEmail checks that an email address is a valid RFC 5322 address
+// (an addr-spec without a display name) whose domain is a valid host name
+// or address literal.
+//
+// The returned error is an *EmailError that wraps one of the ErrEmail
+// errors.
+func ValidateEmail(email string, opts ...EmailOption) error {
+	_, err := NormalizeEmail(email, opts...)
+	return err
+}
+
+// NormalizeEmail validates an email address and returns it with a lower
+// case (and, for internationalized domains, punycode) domain.
+//
+// The local part keeps its case since it may be case sensitive, unless
+// CanonicalizeGmail applies to the address.
+func NormalizeEmail(email string, opts ...EmailOption) (*string, error) {
+	config := emailConfig{}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	fail := func(err error) (*string, error) {
+		return nil, &EmailError{Email: email, Err: err}
+	}
+
+	trimmed := strings.TrimSpace(email)
+	if trimmed == "" {
+		return fail(ErrEmailEmpty)
+	}
+	at := strings.LastIndex(trimmed, "@")
+	if at < 1 || at == len(trimmed)-1 {
+		return fail(ErrEmailSyntax)
+	}
+	local, domain := trimmed[:at], trimmed[at+1:]
+
+	if !isValidEmailLocalPart(local) {
+		return fail(ErrEmailLocalPart)
+	}
+	domain, ok := normalizeEmailDomain(domain, config.allowIDN)
+	if !ok {
+		return fail(ErrEmailDomain)
+	}
+	if config.blockDisposable &&
+		(isDisposableEmailDomain(domain, disposableEmailDomains) ||
+			isDisposableEmailDomain(domain, config.extraDisposableDomains)) {
+		return fail(ErrEmailDisposable)
+	}
+	if config.canonicalizeGmail && Contains(gmailDomains, domain) {
+		local = strings.ToLower(local)
+		if plus := strings.Index(local, "+"); plus >= 0 {
+			local = local[:plus]
+		}
+		local = strings.ReplaceAll(local, ".", "")
+		domain = gmailDomains[0]
+		if local == "" {
+			return fail(ErrEmailLocalPart)
+		}
+	}
+
+	normalized := local + "@" + domain
+	if len(normalized) > maxEmailLength {
+		return fail(ErrEmailSyntax)
+	}
+	return &normalized, nil
+}
+
+// isValidEmailLocalPart checks a dot-atom or quoted-string local part
+func isValidEmailLocalPart(local string) bool {
+	if len(local) > maxEmailLocalPart {
+		return false
+	}
+	if strings.HasPrefix(local, `"`) {
+		return isValidEmailQuotedString(local)
+	}
+	for _, atom := range strings.Split(local, ".") {
+		if atom == "" {
+			return false // leading, trailing or consecutive dots
+		}
+		for _, r := range atom {
+			if !isEmailAtext(r) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+func isEmailAtext(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' ||
+		strings.ContainsRune(emailAtext, r)
+}
+
+func isValidEmailQuotedString(local string) bool {
+	if len(local) < 2 || !strings.HasSuffix(local, `"`) {
+		return false
+	}
+	inner := local[1 : len(local)-1]
+	for i := 0; i < len(inner); i++ {
+		c := inner[i]
+		switch {
+		case c == '\\':
+			i++
+			if i == len(inner) || inner[i] < ' ' && inner[i] != '\t' || inner[i] > '~' {
+				return false
+			}
+		case c == '"' || c < ' ' && c != '\t' || c > '~':
+			return false
+		}
+	}
+	return true
+}
+
+// normalizeEmailDomain lower cases a domain and checks that it is a host
+// name with at least two labels or an address literal
+func normalizeEmailDomain(domain string, allowIDN bool) (string, bool) {
+	if strings.HasPrefix(domain, "[") && strings.HasSuffix(domain, "]") {
+		literal := domain[1 : len(domain)-1]
+		if strings.HasPrefix(strings.ToLower(literal), "ipv6:") {
+			ip := net.ParseIP(literal[len("ipv6:"):])
+			return "[IPv6:" + literal[len("ipv6:"):] + "]", ip != nil && ip.To4() == nil
+		}
+		ip := net.ParseIP(literal)
+		return domain, ip != nil && ip.To4() != nil
+	}
+
+	if !isASCII(domain) {
+		if !allowIDN {
+			return "", false
+		}
+		ascii, err := idna.Lookup.ToASCII(domain)
+		if err != nil {
+			return "", false
+		}
+		domain = ascii
+	}
+	domain = strings.ToLower(domain)
+	labels := strings.Split(domain, ".")
+	if len(labels) < 2 {
+		return "", false
+	}
+	for _, label := range labels {
+		if !isValidHostLabel(label) {
+			return "", false
+		}
+	}
+	return domain, true
+}
+
+func isValidHostLabel(label string) bool {
+	if label == "" || len(label) > maxEmailLabelChars ||
+		strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+		return false
+	}
+	for _, r := range label {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-') {
+			return false
+		}
+	}
+	return true
+}
+
+func isDisposableEmailDomain(domain string, disposable []string) bool {
+	for _, d := range disposable {
+		if domain == d || strings.HasSuffix(domain, "."+d) {
+			return true
+		}
+	}
+	return false
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
+
+// default mailbox used by GenerateRandomEmail
+const (
+	defaultTestEmailMailbox = "be.well"
+	defaultTestEmailDomain  = "bewell.co.ke"
+)
+
+// TestEmailSuffix is the kind of unique suffix that a TestEmailGenerator
+// adds to its mailbox
+type TestEmailSuffix int
+
+// the kinds of unique suffix a TestEmailGenerator can use
+const (
+	// TestEmailSuffixUUID uses a random (version 4) UUID
+	TestEmailSuffixUUID TestEmailSuffix = iota
+
+	// TestEmailSuffixRandom uses 16 random hexadecimal characters, which
+	// gives shorter addresses
+	TestEmailSuffixRandom
+)
+
+// TestEmailGenerator generates unique email addresses that all deliver to
+// one real mailbox through plus addressing e.g
+// be.well+4d0a...@bewell.co.ke.
+//
+// Suffixes come from a cryptographically secure random source, so addresses
+// do not collide even when generated in the same instant. It is safe for
+// concurrent use.
+type TestEmailGenerator struct {
+	mailbox string
+	domain  string
+	suffix  TestEmailSuffix
+}
+
+// NewTestEmailGenerator initializes a generator for the supplied mailbox
+// (the local part of the address, without a plus tag) and domain
+func NewTestEmailGenerator(
+	mailbox, domain string, suffix TestEmailSuffix) (*TestEmailGenerator, error) {
+	if strings.Contains(mailbox, "+") {
+		return nil, fmt.Errorf("the mailbox %s already has a plus tag", mailbox)
+	}
+	if suffix != TestEmailSuffixUUID && suffix != TestEmailSuffixRandom {
+		return nil, fmt.Errorf("unknown test email suffix: %d", suffix)
+	}
+	normalized, err := NormalizeEmail(mailbox + "@" + domain)
+	if err != nil {
+		return nil, err
+	}
+	at := strings.LastIndex(*normalized, "@")
+	return &TestEmailGenerator{
+		mailbox: (*normalized)[:at],
+		domain:  (*normalized)[at+1:],
+		suffix:  suffix,
+	}, nil
+}
+
+// Generate returns a new unique email address
+func (g *TestEmailGenerator) Generate() string {
+	return g.address(g.uniqueSuffix())
+}
+
+func (g *TestEmailGenerator) address(suffix string) string {
+	return fmt.Sprintf("%s+%s@%s", g.mailbox, suffix, g.domain)
+}
+
+func (g *TestEmailGenerator) uniqueSuffix() string {
+	if g.suffix == TestEmailSuffixRandom {
+		b := make([]byte, 8)
+		_, err := rand.Read(b)
+		if err == nil {
+			return hex.EncodeToString(b)
+		}
+		// fall back to a UUID, which reads randomness on its own
+	}
+	return strings.ReplaceAll(uuid.NewV4().String(), "-", "")
+}
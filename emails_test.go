@@ -0,0 +1,279 @@
+package converterandformatter_test
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/savannahghi/converterandformatter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateEmail(t *testing.T) {
+	tests := []struct {
+		name    string
+		email   string
+		opts    []converterandformatter.EmailOption
+		wantErr error
+	}{
+		{
+			name:  "valid : simple",
+			email: "be.well@bewell.co.ke",
+		},
+		{
+			name:  "valid : plus addressing and special characters",
+			email: "o'brien+test!#$%&*/=?^_`{|}~-@example.com",
+		},
+		{
+			name:  "valid : quoted local part",
+			email: `"john doe"@example.com`,
+		},
+		{
+			name:  "valid : escaped quote in quoted local part",
+			email: `"john\"doe"@example.com`,
+		},
+		{
+			name:  "valid : IPv4 address literal",
+			email: "admin@[192.168.0.1]",
+		},
+		{
+			name:  "valid : IPv6 address literal",
+			email: "admin@[IPv6:2001:db8::1]",
+		},
+		{
+			name:  "valid : internationalized domain",
+			email: "info@mfano.кен",
+			opts:  []converterandformatter.EmailOption{converterandformatter.AllowIDN()},
+		},
+		{
+			name:    "invalid : empty",
+			email:   "  ",
+			wantErr: converterandformatter.ErrEmailEmpty,
+		},
+		{
+			name:    "invalid : no at sign",
+			email:   "bewell.co.ke",
+			wantErr: converterandformatter.ErrEmailSyntax,
+		},
+		{
+			name:    "invalid : nothing after the at sign",
+			email:   "be.well@",
+			wantErr: converterandformatter.ErrEmailSyntax,
+		},
+		{
+			name:    "invalid : consecutive dots",
+			email:   "be..well@bewell.co.ke",
+			wantErr: converterandformatter.ErrEmailLocalPart,
+		},
+		{
+			name:    "invalid : space in local part",
+			email:   "be well@bewell.co.ke",
+			wantErr: converterandformatter.ErrEmailLocalPart,
+		},
+		{
+			name:    "invalid : unterminated quoted local part",
+			email:   `"john@example.com`,
+			wantErr: converterandformatter.ErrEmailLocalPart,
+		},
+		{
+			name:    "invalid : local part too long",
+			email:   strings.Repeat("a", 65) + "@example.com",
+			wantErr: converterandformatter.ErrEmailLocalPart,
+		},
+		{
+			name:    "invalid : single label domain",
+			email:   "admin@localhost",
+			wantErr: converterandformatter.ErrEmailDomain,
+		},
+		{
+			name:    "invalid : label starting with a hyphen",
+			email:   "admin@-example.com",
+			wantErr: converterandformatter.ErrEmailDomain,
+		},
+		{
+			name:    "invalid : bad address literal",
+			email:   "admin@[999.1.1.1]",
+			wantErr: converterandformatter.ErrEmailDomain,
+		},
+		{
+			name:    "invalid : internationalized domain not allowed",
+			email:   "info@mfano.кен",
+			wantErr: converterandformatter.ErrEmailDomain,
+		},
+		{
+			name:    "invalid : disposable domain",
+			email:   "someone@mailinator.com",
+			opts:    []converterandformatter.EmailOption{converterandformatter.BlockDisposableDomains()},
+			wantErr: converterandformatter.ErrEmailDisposable,
+		},
+		{
+			name:    "invalid : extra disposable subdomain",
+			email:   "someone@inbox.Throwaway.test",
+			opts:    []converterandformatter.EmailOption{converterandformatter.BlockDisposableDomains("throwaway.test")},
+			wantErr: converterandformatter.ErrEmailDisposable,
+		},
+		{
+			name:  "valid : disposable domain not blocked",
+			email: "someone@mailinator.com",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := converterandformatter.ValidateEmail(tt.email, tt.opts...)
+			if tt.wantErr == nil {
+				assert.Nil(t, err)
+				assert.True(t, converterandformatter.IsEmailValid(tt.email, tt.opts...))
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidateEmail() error = %v, want %v", err, tt.wantErr)
+			}
+			var emailErr *converterandformatter.EmailError
+			assert.True(t, errors.As(err, &emailErr))
+			assert.Equal(t, tt.email, emailErr.Email)
+			assert.False(t, converterandformatter.IsEmailValid(tt.email, tt.opts...))
+		})
+	}
+}
+
+func TestNormalizeEmail(t *testing.T) {
+	tests := []struct {
+		name  string
+		email string
+		opts  []converterandformatter.EmailOption
+		want  string
+	}{
+		{
+			name:  "lower cases the domain only",
+			email: " Be.Well@BeWell.CO.KE ",
+			want:  "Be.Well@bewell.co.ke",
+		},
+		{
+			name:  "gmail left alone by default",
+			email: "Be.Well+test@GMAIL.com",
+			want:  "Be.Well+test@gmail.com",
+		},
+		{
+			name:  "gmail canonicalized",
+			email: "Be.Well+test@GoogleMail.com",
+			opts:  []converterandformatter.EmailOption{converterandformatter.CanonicalizeGmail()},
+			want:  "bewell@gmail.com",
+		},
+		{
+			name:  "other domains not canonicalized",
+			email: "Be.Well+test@bewell.co.ke",
+			opts:  []converterandformatter.EmailOption{converterandformatter.CanonicalizeGmail()},
+			want:  "Be.Well+test@bewell.co.ke",
+		},
+		{
+			name:  "internationalized domain to punycode",
+			email: "info@Mfano.КЕН",
+			opts:  []converterandformatter.EmailOption{converterandformatter.AllowIDN()},
+			want:  "info@mfano.xn--e1ajk",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := converterandformatter.NormalizeEmail(tt.email, tt.opts...)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, *got)
+		})
+	}
+
+	_, err := converterandformatter.NormalizeEmail(
+		"+test@gmail.com", converterandformatter.CanonicalizeGmail())
+	assert.True(t, errors.Is(err, converterandformatter.ErrEmailLocalPart))
+}
+
+func TestNewTestEmailGenerator(t *testing.T) {
+	tests := []struct {
+		name    string
+		mailbox string
+		domain  string
+		suffix  converterandformatter.TestEmailSuffix
+		wantErr bool
+	}{
+		{
+			name:    "uuid suffix",
+			mailbox: "qa.team",
+			domain:  "example.co.ke",
+			suffix:  converterandformatter.TestEmailSuffixUUID,
+		},
+		{
+			name:    "random suffix",
+			mailbox: "qa.team",
+			domain:  "Example.CO.KE",
+			suffix:  converterandformatter.TestEmailSuffixRandom,
+		},
+		{
+			name:    "mailbox with a plus tag",
+			mailbox: "qa+team",
+			domain:  "example.co.ke",
+			wantErr: true,
+		},
+		{
+			name:    "invalid domain",
+			mailbox: "qa.team",
+			domain:  "example",
+			wantErr: true,
+		},
+		{
+			name:    "unknown suffix",
+			mailbox: "qa.team",
+			domain:  "example.co.ke",
+			suffix:  converterandformatter.TestEmailSuffix(7),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g, err := converterandformatter.NewTestEmailGenerator(
+				tt.mailbox, tt.domain, tt.suffix)
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				assert.Nil(t, g)
+				return
+			}
+			assert.Nil(t, err)
+
+			email := g.Generate()
+			assert.True(t, strings.HasPrefix(email, "qa.team+"))
+			assert.True(t, strings.HasSuffix(email, "@example.co.ke"))
+			assert.Nil(t, converterandformatter.ValidateEmail(email))
+		})
+	}
+}
+
+func TestTestEmailGenerator_Unique(t *testing.T) {
+	for _, suffix := range []converterandformatter.TestEmailSuffix{
+		converterandformatter.TestEmailSuffixUUID,
+		converterandformatter.TestEmailSuffixRandom,
+	} {
+		g, err := converterandformatter.NewTestEmailGenerator(
+			"be.well", "bewell.co.ke", suffix)
+		assert.Nil(t, err)
+
+		const workers, perWorker = 8, 100
+		emails := make(chan string, workers*perWorker)
+		var wg sync.WaitGroup
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < perWorker; j++ {
+					emails <- g.Generate()
+				}
+			}()
+		}
+		wg.Wait()
+		close(emails)
+
+		seen := map[string]bool{}
+		for email := range emails {
+			assert.False(t, seen[email], "duplicate email %s", email)
+			seen[email] = true
+		}
+		assert.Len(t, seen, workers*perWorker)
+	}
+}
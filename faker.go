@@ -0,0 +1,293 @@
+package converterandformatter
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MobileCarrier is a mobile network operator whose number ranges the Faker
+// can generate phone numbers from
+type MobileCarrier string
+
+// the mobile carriers that the Faker knows the number ranges of
+const (
+	MobileCarrierSafaricom       MobileCarrier = "SAFARICOM"
+	MobileCarrierAirtelKenya     MobileCarrier = "AIRTEL_KENYA"
+	MobileCarrierTelkomKenya     MobileCarrier = "TELKOM_KENYA"
+	MobileCarrierMTNUganda       MobileCarrier = "MTN_UGANDA"
+	MobileCarrierAirtelUganda    MobileCarrier = "AIRTEL_UGANDA"
+	MobileCarrierVodacomTanzania MobileCarrier = "VODACOM_TANZANIA"
+)
+
+// AllMobileCarriers is a list of all the known mobile carriers
+var AllMobileCarriers = []MobileCarrier{
+	MobileCarrierSafaricom,
+	MobileCarrierAirtelKenya,
+	MobileCarrierTelkomKenya,
+	MobileCarrierMTNUganda,
+	MobileCarrierAirtelUganda,
+	MobileCarrierVodacomTanzania,
+}
+
+// IsValid returns true if the carrier is a known mobile carrier
+func (c MobileCarrier) IsValid() bool {
+	_, ok := carrierNumbering[c]
+	return ok
+}
+
+func (c MobileCarrier) String() string {
+	return string(c)
+}
+
+// Region returns the ISO 3166-1 region code of the country the carrier
+// operates in e.g KE
+func (c MobileCarrier) Region() string {
+	return carrierNumbering[c].region
+}
+
+// carrierNumbering records the country calling code and the first three
+// digits of the (nine digit) national numbers allocated to each carrier
+var carrierNumbering = map[MobileCarrier]struct {
+	region      string
+	callingCode string
+	prefixes    []string
+}{
+	MobileCarrierSafaricom: {
+		region:      "KE",
+		callingCode: "254",
+		prefixes: []string{
+			"700", "701", "702", "703", "704", "705", "706", "707", "708",
+			"710", "711", "712", "713", "714", "715", "716", "717", "718", "719",
+			"720", "721", "722", "723", "724", "725", "726", "727", "728", "729",
+			"740", "741", "742", "743", "745", "746", "748", "757", "758", "759",
+			"768", "769", "790", "791", "792", "793", "794", "795", "796", "797",
+			"798", "799", "110", "111", "112", "113", "114", "115",
+		},
+	},
+	MobileCarrierAirtelKenya: {
+		region:      "KE",
+		callingCode: "254",
+		prefixes: []string{
+			"730", "731", "732", "733", "734", "735", "736", "737", "738", "739",
+			"750", "751", "752", "753", "754", "755", "756", "780", "781", "782",
+			"783", "784", "785", "786", "787", "788", "789", "100", "101", "102",
+		},
+	},
+	MobileCarrierTelkomKenya: {
+		region:      "KE",
+		callingCode: "254",
+		prefixes: []string{
+			"770", "771", "772", "773", "774", "775", "776", "777", "778", "779",
+		},
+	},
+	MobileCarrierMTNUganda: {
+		region:      "UG",
+		callingCode: "256",
+		prefixes: []string{
+			"760", "761", "762", "763", "764", "765", "766", "767", "768", "769",
+			"770", "771", "772", "773", "774", "775", "776", "777", "778", "779",
+			"780", "781", "782", "783", "784", "785", "786", "787", "788", "789",
+		},
+	},
+	MobileCarrierAirtelUganda: {
+		region:      "UG",
+		callingCode: "256",
+		prefixes: []string{
+			"700", "701", "702", "703", "704", "705", "706", "707", "708", "709",
+			"740", "741", "742", "743", "744", "745", "746", "747", "748", "749",
+			"750", "751", "752", "753", "754", "755", "756", "757", "758", "759",
+		},
+	},
+	MobileCarrierVodacomTanzania: {
+		region:      "TZ",
+		callingCode: "255",
+		prefixes: []string{
+			"740", "741", "742", "743", "744", "745", "746", "747", "748", "749",
+			"750", "751", "752", "753", "754", "755", "756", "757", "758", "759",
+			"760", "761", "762", "763", "764", "765", "766", "767", "768", "769",
+		},
+	},
+}
+
+// the carriers picked from when the caller does not ask for one
+var kenyanMobileCarriers = []MobileCarrier{
+	MobileCarrierSafaricom,
+	MobileCarrierAirtelKenya,
+	MobileCarrierTelkomKenya,
+}
+
+var fakeFemaleGivenNames = []string{
+	"Wanjiku", "Akinyi", "Njeri", "Atieno", "Wambui", "Chebet", "Jepkosgei",
+	"Nyambura", "Mumbua", "Kanini", "Nafula", "Khadija", "Zawadi", "Mercy",
+	"Faith", "Grace", "Esther", "Joy", "Sharon", "Mary", "Ann", "Halima",
+}
+
+var fakeMaleGivenNames = []string{
+	"Kamau", "Otieno", "Kiprono", "Mwangi", "Ochieng", "Kipchoge", "Mutua",
+	"Wafula", "Barasa", "Omondi", "Juma", "Baraka", "Hassan", "Brian",
+	"Kevin", "Dennis", "John", "Peter", "James", "David", "Samuel", "Joseph",
+}
+
+var fakeFamilyNames = []string{
+	"Kamau", "Mwangi", "Njoroge", "Kariuki", "Wanjiru", "Otieno", "Odhiambo",
+	"Onyango", "Ouma", "Kiprotich", "Cheruiyot", "Rotich", "Kipkemboi",
+	"Mutiso", "Musyoka", "Wambua", "Wekesa", "Wafula", "Simiyu", "Nyaga",
+	"Mohamed", "Abdi", "Hussein", "Ali", "Mwakio", "Kazungu", "Lekishon",
+}
+
+// FakeContact is a realistic looking but fake person's contact record
+type FakeContact struct {
+	FirstName   string    `json:"firstName"`
+	LastName    string    `json:"lastName"`
+	MSISDN      string    `json:"msisdn"`
+	Email       string    `json:"email"`
+	NationalID  string    `json:"nationalID"`
+	DateOfBirth time.Time `json:"dateOfBirth"`
+}
+
+// FakerOption configures a Faker
+type FakerOption func(*Faker)
+
+// WithFakeEmailGenerator makes the Faker generate emails for the generator's
+// mailbox instead of the be.well@bewell.co.ke one. A nil generator is
+// ignored.
+func WithFakeEmailGenerator(g *TestEmailGenerator) FakerOption {
+	return func(f *Faker) {
+		if g != nil {
+			f.emails = g
+		}
+	}
+}
+
+// defaultFakerReferenceTime is the instant that ages are measured from unless
+// WithReferenceTime is used. It is fixed, rather than the current time, so
+// that the dates of birth generated from a seed do not change from day to
+// day.
+var defaultFakerReferenceTime = time.Date(2021, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// WithReferenceTime sets the instant that ages are measured from when
+// generating dates of birth. It defaults to 1 January 2021 (UTC).
+func WithReferenceTime(t time.Time) FakerOption {
+	return func(f *Faker) {
+		f.now = t
+	}
+}
+
+// Faker generates realistic looking but fake Kenyan contact data for tests.
+//
+// Two fakers created with the same seed and options produce the same sequence
+// of values, which keeps tests that use them deterministic. It is
+// safe for concurrent use, although the order of values handed to concurrent
+// callers is not deterministic.
+type Faker struct {
+	mu     sync.Mutex
+	rnd    *rand.Rand
+	emails *TestEmailGenerator
+	now    time.Time
+}
+
+// NewFaker initializes a Faker whose values are derived from the seed
+func NewFaker(seed int64, opts ...FakerOption) *Faker {
+	f := &Faker{
+		rnd:    rand.New(rand.NewSource(seed)), // #nosec G404 -- fake test data must be reproducible
+		emails: defaultTestEmailGenerator,
+		now:    defaultFakerReferenceTime,
+	}
+	for _, opt := range opts {
+		opt(f)
+	}
+	return f
+}
+
+func (f *Faker) intn(n int) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.rnd.Intn(n)
+}
+
+func (f *Faker) pick(values []string) string {
+	return values[f.intn(len(values))]
+}
+
+// MSISDN returns a phone number, in international format e.g +254722123456,
+// from the number ranges of a randomly chosen Kenyan carrier
+func (f *Faker) MSISDN() string {
+	carrier := kenyanMobileCarriers[f.intn(len(kenyanMobileCarriers))]
+	msisdn, _ := f.CarrierMSISDN(carrier)
+	return msisdn
+}
+
+// CarrierMSISDN returns a phone number, in international format, from the
+// number ranges of the supplied carrier
+func (f *Faker) CarrierMSISDN(carrier MobileCarrier) (string, error) {
+	numbering, ok := carrierNumbering[carrier]
+	if !ok {
+		return "", fmt.Errorf("unknown mobile carrier: %s", carrier)
+	}
+	return fmt.Sprintf(
+		"+%s%s%06d",
+		numbering.callingCode,
+		f.pick(numbering.prefixes),
+		f.intn(1000000),
+	), nil
+}
+
+// Email returns a unique plus-addressed email for the Faker's mailbox
+func (f *Faker) Email() string {
+	f.mu.Lock()
+	suffix := fmt.Sprintf("%016x", f.rnd.Uint64())
+	f.mu.Unlock()
+	return f.emails.address(suffix)
+}
+
+// FirstName returns a Kenyan given name
+func (f *Faker) FirstName() string {
+	if f.intn(2) == 0 {
+		return f.pick(fakeFemaleGivenNames)
+	}
+	return f.pick(fakeMaleGivenNames)
+}
+
+// LastName returns a Kenyan family name
+func (f *Faker) LastName() string {
+	return f.pick(fakeFamilyNames)
+}
+
+// NationalID returns an eight digit national ID number
+func (f *Faker) NationalID() string {
+	return fmt.Sprintf("%d", 10000000+f.intn(30000000))
+}
+
+// DateOfBirth returns a date of birth for someone who is at least minAge and
+// at most maxAge years old at the Faker's reference time
+func (f *Faker) DateOfBirth(minAge, maxAge int) (time.Time, error) {
+	if minAge < 0 || maxAge < minAge {
+		return time.Time{}, fmt.Errorf(
+			"invalid age range: %d to %d years", minAge, maxAge)
+	}
+	ref := time.Date(f.now.Year(), f.now.Month(), f.now.Day(), 0, 0, 0, 0, time.UTC)
+	latest := ref.AddDate(-minAge, 0, 0)
+	earliest := ref.AddDate(-maxAge-1, 0, 1)
+	days := int(latest.Sub(earliest).Hours()/24) + 1
+	return earliest.AddDate(0, 0, f.intn(days)), nil
+}
+
+// Contact returns a fake adult's contact record
+func (f *Faker) Contact() FakeContact {
+	dob, _ := f.DateOfBirth(18, 80)
+	first, last := f.FirstName(), f.LastName()
+	for strings.EqualFold(first, last) {
+		last = f.LastName()
+	}
+	return FakeContact{
+		FirstName:   first,
+		LastName:    last,
+		MSISDN:      f.MSISDN(),
+		Email:       f.Email(),
+		NationalID:  f.NationalID(),
+		DateOfBirth: dob,
+	}
+}
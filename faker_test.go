@@ -0,0 +1,112 @@
+package converterandformatter_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/savannahghi/converterandformatter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFaker_Reproducible(t *testing.T) {
+	ref := time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+	first := converterandformatter.NewFaker(
+		42, converterandformatter.WithReferenceTime(ref))
+	second := converterandformatter.NewFaker(
+		42, converterandformatter.WithReferenceTime(ref))
+	other := converterandformatter.NewFaker(
+		43, converterandformatter.WithReferenceTime(ref))
+
+	a, b, c := first.Contact(), second.Contact(), other.Contact()
+	assert.Equal(t, a, b)
+	assert.NotEqual(t, a, c)
+}
+
+func TestFaker_DefaultReferenceTime(t *testing.T) {
+	first := converterandformatter.NewFaker(7)
+	second := converterandformatter.NewFaker(7)
+
+	dob, err := first.DateOfBirth(30, 30)
+	assert.Nil(t, err)
+	again, err := second.DateOfBirth(30, 30)
+	assert.Nil(t, err)
+	assert.Equal(t, dob, again)
+
+	age, err := converterandformatter.AgeAt(dob, time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+	assert.Nil(t, err)
+	assert.Equal(t, 30, age)
+}
+
+func TestFaker_NilEmailGenerator(t *testing.T) {
+	f := converterandformatter.NewFaker(
+		5, converterandformatter.WithFakeEmailGenerator(nil))
+	email := f.Email()
+	assert.True(t, strings.HasSuffix(email, "@bewell.co.ke"), email)
+}
+
+func TestFaker_CarrierMSISDN(t *testing.T) {
+	f := converterandformatter.NewFaker(1)
+	for _, carrier := range converterandformatter.AllMobileCarriers {
+		t.Run(carrier.String(), func(t *testing.T) {
+			assert.True(t, carrier.IsValid())
+			for i := 0; i < 50; i++ {
+				msisdn, err := f.CarrierMSISDN(carrier)
+				assert.Nil(t, err)
+				assert.True(t, converterandformatter.IsMSISDNValid(msisdn))
+
+				normalized, err := converterandformatter.NormalizeMSISDN(msisdn)
+				assert.Nil(t, err)
+				assert.Equal(t, msisdn, *normalized)
+			}
+		})
+	}
+
+	_, err := f.CarrierMSISDN(converterandformatter.MobileCarrier("UNKNOWN"))
+	assert.NotNil(t, err)
+	assert.Equal(t, "UG", converterandformatter.MobileCarrierMTNUganda.Region())
+}
+
+func TestFaker_Contact(t *testing.T) {
+	g, err := converterandformatter.NewTestEmailGenerator(
+		"qa", "example.co.ke", converterandformatter.TestEmailSuffixRandom)
+	assert.Nil(t, err)
+	ref := time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+	f := converterandformatter.NewFaker(
+		7,
+		converterandformatter.WithReferenceTime(ref),
+		converterandformatter.WithFakeEmailGenerator(g),
+	)
+
+	emails := map[string]bool{}
+	for i := 0; i < 100; i++ {
+		c := f.Contact()
+		assert.NotEmpty(t, c.FirstName)
+		assert.NotEmpty(t, c.LastName)
+		assert.True(t, strings.HasPrefix(c.MSISDN, "+254"))
+		assert.Len(t, c.NationalID, 8)
+		assert.Nil(t, converterandformatter.ValidateEmail(c.Email))
+		assert.True(t, strings.HasSuffix(c.Email, "@example.co.ke"))
+		assert.False(t, emails[c.Email])
+		emails[c.Email] = true
+
+		assert.False(t, c.DateOfBirth.After(ref.AddDate(-18, 0, 0)))
+		assert.True(t, c.DateOfBirth.After(ref.AddDate(-81, 0, 0)))
+	}
+}
+
+func TestFaker_DateOfBirth(t *testing.T) {
+	ref := time.Date(2021, 7, 1, 0, 0, 0, 0, time.UTC)
+	f := converterandformatter.NewFaker(
+		3, converterandformatter.WithReferenceTime(ref))
+
+	dob, err := f.DateOfBirth(30, 30)
+	assert.Nil(t, err)
+	assert.False(t, dob.After(time.Date(1991, 7, 1, 0, 0, 0, 0, time.UTC)))
+	assert.False(t, dob.Before(time.Date(1990, 7, 2, 0, 0, 0, 0, time.UTC)))
+
+	_, err = f.DateOfBirth(40, 30)
+	assert.NotNil(t, err)
+	_, err = f.DateOfBirth(-1, 30)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,235 @@
+package converterandformatter
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// FlattenOption changes how Flatten and Unflatten treat nested values
+type FlattenOption func(*flattenConfig)
+
+type flattenConfig struct {
+	keepArrays bool
+	maxDepth   int
+}
+
+func newFlattenConfig(opts []FlattenOption) flattenConfig {
+	config := flattenConfig{}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return config
+}
+
+// KeepArrays leaves lists as single values instead of flattening them into
+// one key per item
+func KeepArrays() FlattenOption {
+	return func(c *flattenConfig) {
+		c.keepArrays = true
+	}
+}
+
+// WithMaxDepth limits the number of key segments that Flatten produces.
+// Values below that depth are kept nested. Zero means no limit.
+func WithMaxDepth(depth int) FlattenOption {
+	return func(c *flattenConfig) {
+		c.maxDepth = depth
+	}
+}
+
+// Flatten converts a nested map, such as one produced by StructToTypedMap,
+// into a map with a single level of keys made by joining the keys of the
+// nested values with sep e.g "field.sample.name".
+//
+// List items are keyed by their index e.g "items.0.id" unless KeepArrays is
+// used. Empty maps and lists are kept as values so that Unflatten can restore
+// them. Like Unflatten, Flatten returns an error when sep is empty or when
+// two values end up with the same key e.g "a.b" and "a" holding "b".
+func Flatten(in map[string]interface{}, sep string, opts ...FlattenOption) (map[string]interface{}, error) {
+	if sep == "" {
+		return nil, fmt.Errorf("a key separator is required")
+	}
+	config := newFlattenConfig(opts)
+	out := map[string]interface{}{}
+	for _, k := range sortedKeys(in) {
+		err := flattenValue(out, k, reflect.ValueOf(in[k]), sep, 1, config)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+func flattenValue(
+	out map[string]interface{}, key string, v reflect.Value, sep string,
+	depth int, config flattenConfig) error {
+	for v.IsValid() && v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+	switch {
+	case !v.IsValid():
+		return setFlatValue(out, key, nil)
+	case config.maxDepth > 0 && depth >= config.maxDepth:
+		return setFlatValue(out, key, v.Interface())
+	case v.Kind() == reflect.Map && v.Type().Key().Kind() == reflect.String && v.Len() > 0:
+		iter := v.MapRange()
+		for iter.Next() {
+			err := flattenValue(out, key+sep+iter.Key().String(), iter.Value(), sep, depth+1, config)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	case (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) &&
+		v.Type().Elem().Kind() != reflect.Uint8 && !config.keepArrays && v.Len() > 0:
+		for i := 0; i < v.Len(); i++ {
+			err := flattenValue(out, key+sep+strconv.Itoa(i), v.Index(i), sep, depth+1, config)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return setFlatValue(out, key, v.Interface())
+	}
+}
+
+func setFlatValue(out map[string]interface{}, key string, value interface{}) error {
+	if _, ok := out[key]; ok {
+		return fmt.Errorf("more than one value is flattened to the key %q", key)
+	}
+	out[key] = value
+	return nil
+}
+
+func sortedKeys(in map[string]interface{}) []string {
+	keys := make([]string, 0, len(in))
+	for k := range in {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// flatNode is a map built by Unflatten from the segments of flattened keys,
+// as opposed to a map that was a value in the flattened input
+type flatNode map[string]interface{}
+
+// Unflatten is the inverse of Flatten: it splits keys on sep and rebuilds
+// the nested maps. The values of the input are copied so that changing the
+// output does not change the input.
+//
+// Unless KeepArrays is used, a level built from keys that are exactly 0 to
+// n-1, as Flatten writes list items, is rebuilt as a list. Maps that were
+// values in the input are never turned into lists. An error is returned when
+// sep is empty or when a key is both a value and the parent of other keys
+// e.g "a" and "a.b".
+func Unflatten(in map[string]interface{}, sep string, opts ...FlattenOption) (map[string]interface{}, error) {
+	if sep == "" {
+		return nil, fmt.Errorf("a key separator is required")
+	}
+	config := newFlattenConfig(opts)
+
+	root := flatNode{}
+	for _, key := range sortedKeys(in) {
+		parts := strings.Split(key, sep)
+		node := root
+		for i, part := range parts[:len(parts)-1] {
+			child, ok := node[part]
+			if !ok {
+				next := flatNode{}
+				node[part] = next
+				node = next
+				continue
+			}
+			next, ok := child.(flatNode)
+			if !ok {
+				return nil, fmt.Errorf(
+					"key %q conflicts with the value at %q",
+					key, strings.Join(parts[:i+1], sep))
+			}
+			node = next
+		}
+		last := parts[len(parts)-1]
+		if _, ok := node[last]; ok {
+			return nil, fmt.Errorf("key %q conflicts with a nested key", key)
+		}
+		node[last] = copyFlatValue(reflect.ValueOf(in[key]))
+	}
+
+	out := map[string]interface{}{}
+	for k, child := range root {
+		out[k] = buildUnflattened(child, !config.keepArrays)
+	}
+	return out, nil
+}
+
+// buildUnflattened turns the nodes built by Unflatten into maps or, when
+// their keys are list indexes and arrays are restored, into lists
+func buildUnflattened(v interface{}, restoreArrays bool) interface{} {
+	node, ok := v.(flatNode)
+	if !ok {
+		return v
+	}
+	m := make(map[string]interface{}, len(node))
+	for k, child := range node {
+		m[k] = buildUnflattened(child, restoreArrays)
+	}
+	if !restoreArrays {
+		return m
+	}
+	items := make([]interface{}, len(m))
+	for k, child := range m {
+		i, err := strconv.Atoi(k)
+		if err != nil || i < 0 || i >= len(m) || strconv.Itoa(i) != k {
+			return m
+		}
+		items[i] = child
+	}
+	return items
+}
+
+// copyFlatValue returns a deep copy of the maps and slices in a value. Other
+// values, such as structs and pointers, are returned as they are.
+func copyFlatValue(v reflect.Value) interface{} {
+	if !v.IsValid() {
+		return nil
+	}
+	return copyReflectValue(v).Interface()
+}
+
+func copyReflectValue(v reflect.Value) reflect.Value {
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.New(v.Type()).Elem()
+		out.Set(copyReflectValue(v.Elem()))
+		return out
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeMapWithSize(v.Type(), v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			out.SetMapIndex(iter.Key(), copyReflectValue(iter.Value()))
+		}
+		return out
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		out := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			out.Index(i).Set(copyReflectValue(v.Index(i)))
+		}
+		return out
+	default:
+		return v
+	}
+}
@@ -0,0 +1,205 @@
+package converterandformatter_test
+
+import (
+	"testing"
+
+	"github.com/savannahghi/converterandformatter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlatten(t *testing.T) {
+	in := map[string]interface{}{
+		"field": map[string]interface{}{
+			"one_point": "yuhuhuu",
+			"sample":    map[string]interface{}{"name": "John Doe", "id": "12121"},
+		},
+		"items": []interface{}{
+			map[string]interface{}{"id": 1},
+			map[string]interface{}{"id": 2},
+		},
+		"tags":  []string{"a", "b"},
+		"raw":   []byte("raw"),
+		"empty": map[string]interface{}{},
+		"none":  []interface{}{},
+		"nil":   nil,
+	}
+
+	tests := []struct {
+		name string
+		opts []converterandformatter.FlattenOption
+		want map[string]interface{}
+	}{
+		{
+			name: "defaults",
+			want: map[string]interface{}{
+				"field.one_point":   "yuhuhuu",
+				"field.sample.name": "John Doe",
+				"field.sample.id":   "12121",
+				"items.0.id":        1,
+				"items.1.id":        2,
+				"tags.0":            "a",
+				"tags.1":            "b",
+				"raw":               []byte("raw"),
+				"empty":             map[string]interface{}{},
+				"none":              []interface{}{},
+				"nil":               nil,
+			},
+		},
+		{
+			name: "keep arrays and limit depth",
+			opts: []converterandformatter.FlattenOption{
+				converterandformatter.KeepArrays(),
+				converterandformatter.WithMaxDepth(2),
+			},
+			want: map[string]interface{}{
+				"field.one_point": "yuhuhuu",
+				"field.sample":    map[string]interface{}{"name": "John Doe", "id": "12121"},
+				"items": []interface{}{
+					map[string]interface{}{"id": 1},
+					map[string]interface{}{"id": 2},
+				},
+				"tags":  []string{"a", "b"},
+				"raw":   []byte("raw"),
+				"empty": map[string]interface{}{},
+				"none":  []interface{}{},
+				"nil":   nil,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := converterandformatter.Flatten(in, ".", tt.opts...)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFlatten_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		in   map[string]interface{}
+		sep  string
+	}{
+		{
+			name: "colliding keys",
+			in: map[string]interface{}{
+				"a.b": 1,
+				"a":   map[string]interface{}{"b": 2},
+			},
+			sep: ".",
+		},
+		{
+			name: "missing separator",
+			in:   map[string]interface{}{"a": 1},
+			sep:  "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := converterandformatter.Flatten(tt.in, tt.sep)
+			assert.NotNil(t, err)
+		})
+	}
+}
+
+func TestUnflatten(t *testing.T) {
+	in := map[string]interface{}{
+		"field/sample/name": "John Doe",
+		"items/0/id":        1,
+		"items/1/id":        2,
+		"codes/1":           "sparse",
+		"empty":             map[string]interface{}{},
+	}
+
+	got, err := converterandformatter.Unflatten(in, "/")
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"field": map[string]interface{}{
+			"sample": map[string]interface{}{"name": "John Doe"},
+		},
+		"items": []interface{}{
+			map[string]interface{}{"id": 1},
+			map[string]interface{}{"id": 2},
+		},
+		"codes": map[string]interface{}{"1": "sparse"},
+		"empty": map[string]interface{}{},
+	}, got)
+
+	got, err = converterandformatter.Unflatten(in, "/", converterandformatter.KeepArrays())
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"0": map[string]interface{}{"id": 1},
+		"1": map[string]interface{}{"id": 2},
+	}, got["items"])
+}
+
+func TestFlattenUnflattenRoundTrip(t *testing.T) {
+	in := map[string]interface{}{
+		"a": map[string]interface{}{
+			"b": []interface{}{"x", map[string]interface{}{"c": true}},
+		},
+		"d": 4,
+	}
+	flat, err := converterandformatter.Flatten(in, ".")
+	assert.Nil(t, err)
+	got, err := converterandformatter.Unflatten(flat, ".")
+	assert.Nil(t, err)
+	assert.Equal(t, in, got)
+}
+
+func TestUnflatten_CopiesValues(t *testing.T) {
+	in := map[string]interface{}{
+		"a.b":   []interface{}{"x"},
+		"a.c":   map[string]interface{}{"0": "zero", "1": "one"},
+		"empty": map[string]interface{}{},
+	}
+	got, err := converterandformatter.Unflatten(in, ".")
+	assert.Nil(t, err)
+
+	// a map that was a value in the input is not turned into a list
+	a := got["a"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"0": "zero", "1": "one"}, a["c"])
+
+	a["b"].([]interface{})[0] = "changed"
+	a["c"].(map[string]interface{})["2"] = "two"
+	got["empty"].(map[string]interface{})["new"] = true
+	assert.Equal(t, []interface{}{"x"}, in["a.b"])
+	assert.Equal(t, map[string]interface{}{"0": "zero", "1": "one"}, in["a.c"])
+	assert.Equal(t, map[string]interface{}{}, in["empty"])
+}
+
+func TestUnflatten_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		in   map[string]interface{}
+		sep  string
+	}{
+		{
+			name: "value and parent",
+			in:   map[string]interface{}{"a": 1, "a.b": 2},
+			sep:  ".",
+		},
+		{
+			name: "parent and value",
+			in:   map[string]interface{}{"a.b": 1, "a.b.c": 2},
+			sep:  ".",
+		},
+		{
+			name: "value map and parent",
+			in:   map[string]interface{}{"a": map[string]interface{}{}, "a.b": 2},
+			sep:  ".",
+		},
+		{
+			name: "missing separator",
+			in:   map[string]interface{}{"a": 1},
+			sep:  "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := converterandformatter.Unflatten(tt.in, tt.sep)
+			assert.NotNil(t, err)
+		})
+	}
+}
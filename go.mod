@@ -1,6 +1,6 @@
 module github.com/savannahghi/converterandformatter
 
-go 1.16
+go 1.18
 
 require (
 	cloud.google.com/go/firestore v1.5.0
@@ -8,6 +8,52 @@ require (
 	github.com/savannahghi/firebasetools v0.0.15
 	github.com/savannahghi/serverutils v0.0.4
 	github.com/stretchr/testify v1.7.0
-	github.com/ttacon/builder v0.0.0-20170518171403-c099f663e1c2 // indirect
 	github.com/ttacon/libphonenumber v1.2.1
+	golang.org/x/net v0.0.0-20210503060351-7fd8e65b6420
+	golang.org/x/text v0.3.6
+	google.golang.org/grpc v1.38.0
+)
+
+require (
+	cloud.google.com/go v0.84.0 // indirect
+	cloud.google.com/go/logging v1.4.2 // indirect
+	cloud.google.com/go/storage v1.10.0 // indirect
+	contrib.go.opencensus.io/exporter/stackdriver v0.13.6 // indirect
+	firebase.google.com/go v3.13.0+incompatible // indirect
+	github.com/aws/aws-sdk-go v1.37.0 // indirect
+	github.com/census-instrumentation/opencensus-proto v0.3.0 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/getsentry/sentry-go v0.11.0 // indirect
+	github.com/gofrs/uuid v4.0.0+incompatible // indirect
+	github.com/golang/groupcache v0.0.0-20200121045136-8c9f03a8e57e // indirect
+	github.com/golang/protobuf v1.5.2 // indirect
+	github.com/google/go-cmp v0.5.6 // indirect
+	github.com/google/pprof v0.0.0-20210601050228-01bbb1931b22 // indirect
+	github.com/google/uuid v1.2.0 // indirect
+	github.com/googleapis/gax-go/v2 v2.0.5 // indirect
+	github.com/jmespath/go-jmespath v0.4.0 // indirect
+	github.com/jstemmer/go-junit-report v0.9.1 // indirect
+	github.com/lithammer/shortuuid v3.0.0+incompatible // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/savannahghi/enumutils v0.0.3 // indirect
+	github.com/sirupsen/logrus v1.8.1 // indirect
+	github.com/ttacon/builder v0.0.0-20170518171403-c099f663e1c2 // indirect
+	github.com/vmihailenco/msgpack v4.0.4+incompatible // indirect
+	go.opencensus.io v0.23.0 // indirect
+	go.opentelemetry.io/otel v1.0.0-RC1 // indirect
+	go.opentelemetry.io/otel/exporters/jaeger v1.0.0-RC1 // indirect
+	go.opentelemetry.io/otel/sdk v1.0.0-RC1 // indirect
+	go.opentelemetry.io/otel/trace v1.0.0-RC1 // indirect
+	golang.org/x/lint v0.0.0-20210508222113-6edffad5e616 // indirect
+	golang.org/x/mod v0.4.2 // indirect
+	golang.org/x/oauth2 v0.0.0-20210514164344-f6687ab2804c // indirect
+	golang.org/x/sync v0.0.0-20210220032951-036812b2e83c // indirect
+	golang.org/x/sys v0.0.0-20210603125802-9665404d3644 // indirect
+	golang.org/x/tools v0.1.2 // indirect
+	golang.org/x/xerrors v0.0.0-20200804184101-5ec99f83aff1 // indirect
+	google.golang.org/api v0.48.0 // indirect
+	google.golang.org/appengine v1.6.7 // indirect
+	google.golang.org/genproto v0.0.0-20210608205507-b6d2f5bf0d7d // indirect
+	google.golang.org/protobuf v1.26.0 // indirect
+	gopkg.in/yaml.v3 v3.0.0-20210107192922-496545a6307b // indirect
 )
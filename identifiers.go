@@ -0,0 +1,216 @@
+package converterandformatter
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// IdentifierType is a kind of Kenyan personal identification document
+type IdentifierType string
+
+// the identification documents that can be validated
+const (
+	IdentifierTypeNationalID       IdentifierType = "NATIONAL_ID"
+	IdentifierTypePassport         IdentifierType = "PASSPORT"
+	IdentifierTypeKRAPIN           IdentifierType = "KRA_PIN"
+	IdentifierTypeNHIF             IdentifierType = "NHIF"
+	IdentifierTypeSHA              IdentifierType = "SHA"
+	IdentifierTypeAlienID          IdentifierType = "ALIEN_ID"
+	IdentifierTypeBirthCertificate IdentifierType = "BIRTH_CERTIFICATE"
+)
+
+// AllIdentifierTypes is a list of all the known identifier types
+var AllIdentifierTypes = []IdentifierType{
+	IdentifierTypeNationalID,
+	IdentifierTypePassport,
+	IdentifierTypeKRAPIN,
+	IdentifierTypeNHIF,
+	IdentifierTypeSHA,
+	IdentifierTypeAlienID,
+	IdentifierTypeBirthCertificate,
+}
+
+// IsValid returns true if the identifier type is known
+func (t IdentifierType) IsValid() bool {
+	switch t {
+	case IdentifierTypeNationalID, IdentifierTypePassport,
+		IdentifierTypeKRAPIN, IdentifierTypeNHIF, IdentifierTypeSHA,
+		IdentifierTypeAlienID, IdentifierTypeBirthCertificate:
+		return true
+	}
+	return false
+}
+
+func (t IdentifierType) String() string {
+	return string(t)
+}
+
+// errors returned, wrapped in an IdentifierError, by ValidateIdentifier and
+// NormalizeIdentifier. Use errors.Is to tell them apart.
+var (
+	ErrIdentifierEmpty  = errors.New("identifier is empty")
+	ErrIdentifierFormat = errors.New("identifier has an invalid format")
+	ErrIdentifierType   = errors.New("unknown identifier type")
+)
+
+// IdentifierError is returned when an identifier fails validation
+type IdentifierError struct {
+	Type  IdentifierType
+	Value string
+	Err   error
+}
+
+func (e *IdentifierError) Error() string {
+	return fmt.Sprintf("%v: %s %s", e.Err, e.Type, e.Value)
+}
+
+// Unwrap returns the reason the identifier failed validation e.g
+// ErrIdentifierFormat
+func (e *IdentifierError) Unwrap() error {
+	return e.Err
+}
+
+// identifierPatterns match the normalized form of each identifier type
+var identifierPatterns = map[IdentifierType]*regexp.Regexp{
+	// six to eight digits, without leading zeros
+	IdentifierTypeNationalID: regexp.MustCompile(`^[1-9][0-9]{5,7}$`),
+	// e.g A1234567 (older booklets) or AK0123456 (East African e-passports)
+	IdentifierTypePassport: regexp.MustCompile(`^[A-Z]{1,2}[0-9]{6,7}$`),
+	// A (individuals) or P (non individuals), nine digits and a check letter
+	IdentifierTypeKRAPIN: regexp.MustCompile(`^[AP][0-9]{9}[A-Z]$`),
+	IdentifierTypeNHIF:   regexp.MustCompile(`^[0-9]{6,9}$`),
+	// e.g CR1234567890123-4
+	IdentifierTypeSHA:              regexp.MustCompile(`^CR[0-9]{13}-[0-9]$`),
+	IdentifierTypeAlienID:          regexp.MustCompile(`^[0-9]{6,9}$`),
+	IdentifierTypeBirthCertificate: regexp.MustCompile(`^[0-9]{6,10}$`),
+}
+
+// IsIdentifierValid checks that a value is a valid identifier of the
+// supplied type
+func IsIdentifierValid(identifierType IdentifierType, value string) bool {
+	return ValidateIdentifier(identifierType, value) == nil
+}
+
+// ValidateIdentifier checks that a value is a valid identifier of the
+// supplied type.
+//
+// The returned error is an *IdentifierError that wraps one of the
+// ErrIdentifier errors.
+func ValidateIdentifier(identifierType IdentifierType, value string) error {
+	_, err := NormalizeIdentifier(identifierType, value)
+	return err
+}
+
+// NormalizeIdentifier validates an identifier and returns it in its
+// canonical form: upper case, without spaces, and without separators other
+// than the hyphen before an SHA number's check digit e.g " a 123456789 z"
+// becomes "A123456789Z"
+func NormalizeIdentifier(
+	identifierType IdentifierType, value string) (*string, error) {
+	fail := func(err error) (*string, error) {
+		return nil, &IdentifierError{Type: identifierType, Value: value, Err: err}
+	}
+	pattern, ok := identifierPatterns[identifierType]
+	if !ok {
+		return fail(ErrIdentifierType)
+	}
+	normalized := strings.Map(func(r rune) rune {
+		if unicode.IsSpace(r) || r == '-' || r == '/' {
+			return -1
+		}
+		return unicode.ToUpper(r)
+	}, value)
+	if normalized == "" {
+		return fail(ErrIdentifierEmpty)
+	}
+	if identifierType == IdentifierTypeSHA && len(normalized) > 1 {
+		last := len(normalized) - 1
+		normalized = normalized[:last] + "-" + normalized[last:]
+	}
+	if !pattern.MatchString(normalized) {
+		return fail(ErrIdentifierFormat)
+	}
+	return &normalized, nil
+}
+
+// ValidateNationalID checks that a value is a valid national ID number
+func ValidateNationalID(value string) error {
+	return ValidateIdentifier(IdentifierTypeNationalID, value)
+}
+
+// NormalizeNationalID validates a national ID number and returns it in its
+// canonical form
+func NormalizeNationalID(value string) (*string, error) {
+	return NormalizeIdentifier(IdentifierTypeNationalID, value)
+}
+
+// ValidatePassportNumber checks that a value is a valid passport number
+func ValidatePassportNumber(value string) error {
+	return ValidateIdentifier(IdentifierTypePassport, value)
+}
+
+// NormalizePassportNumber validates a passport number and returns it in its
+// canonical form
+func NormalizePassportNumber(value string) (*string, error) {
+	return NormalizeIdentifier(IdentifierTypePassport, value)
+}
+
+// ValidateKRAPIN checks that a value is a valid KRA PIN e.g A123456789Z
+func ValidateKRAPIN(value string) error {
+	return ValidateIdentifier(IdentifierTypeKRAPIN, value)
+}
+
+// NormalizeKRAPIN validates a KRA PIN and returns it in its canonical form
+func NormalizeKRAPIN(value string) (*string, error) {
+	return NormalizeIdentifier(IdentifierTypeKRAPIN, value)
+}
+
+// ValidateNHIFNumber checks that a value is a valid NHIF member number
+func ValidateNHIFNumber(value string) error {
+	return ValidateIdentifier(IdentifierTypeNHIF, value)
+}
+
+// NormalizeNHIFNumber validates an NHIF member number and returns it in its
+// canonical form
+func NormalizeNHIFNumber(value string) (*string, error) {
+	return NormalizeIdentifier(IdentifierTypeNHIF, value)
+}
+
+// ValidateSHANumber checks that a value is a valid Social Health Authority
+// member number e.g CR1234567890123-4
+func ValidateSHANumber(value string) error {
+	return ValidateIdentifier(IdentifierTypeSHA, value)
+}
+
+// NormalizeSHANumber validates a Social Health Authority member number and
+// returns it in its canonical form
+func NormalizeSHANumber(value string) (*string, error) {
+	return NormalizeIdentifier(IdentifierTypeSHA, value)
+}
+
+// ValidateAlienID checks that a value is a valid alien (foreign national)
+// ID number
+func ValidateAlienID(value string) error {
+	return ValidateIdentifier(IdentifierTypeAlienID, value)
+}
+
+// NormalizeAlienID validates an alien ID number and returns it in its
+// canonical form
+func NormalizeAlienID(value string) (*string, error) {
+	return NormalizeIdentifier(IdentifierTypeAlienID, value)
+}
+
+// ValidateBirthCertificateNumber checks that a value is a valid birth
+// certificate entry number
+func ValidateBirthCertificateNumber(value string) error {
+	return ValidateIdentifier(IdentifierTypeBirthCertificate, value)
+}
+
+// NormalizeBirthCertificateNumber validates a birth certificate entry number
+// and returns it in its canonical form
+func NormalizeBirthCertificateNumber(value string) (*string, error) {
+	return NormalizeIdentifier(IdentifierTypeBirthCertificate, value)
+}
@@ -0,0 +1,230 @@
+package converterandformatter_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/savannahghi/converterandformatter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeIdentifier(t *testing.T) {
+	tests := []struct {
+		name           string
+		identifierType converterandformatter.IdentifierType
+		value          string
+		want           string
+		wantErr        error
+	}{
+		{
+			name:           "national ID",
+			identifierType: converterandformatter.IdentifierTypeNationalID,
+			value:          " 12 345 678 ",
+			want:           "12345678",
+		},
+		{
+			name:           "seven digit national ID",
+			identifierType: converterandformatter.IdentifierTypeNationalID,
+			value:          "1234567",
+			want:           "1234567",
+		},
+		{
+			name:           "national ID with a leading zero",
+			identifierType: converterandformatter.IdentifierTypeNationalID,
+			value:          "01234567",
+			wantErr:        converterandformatter.ErrIdentifierFormat,
+		},
+		{
+			name:           "national ID with letters",
+			identifierType: converterandformatter.IdentifierTypeNationalID,
+			value:          "1234567A",
+			wantErr:        converterandformatter.ErrIdentifierFormat,
+		},
+		{
+			name:           "old passport",
+			identifierType: converterandformatter.IdentifierTypePassport,
+			value:          "a1234567",
+			want:           "A1234567",
+		},
+		{
+			name:           "e-passport",
+			identifierType: converterandformatter.IdentifierTypePassport,
+			value:          "AK 0123456",
+			want:           "AK0123456",
+		},
+		{
+			name:           "passport without a letter",
+			identifierType: converterandformatter.IdentifierTypePassport,
+			value:          "01234567",
+			wantErr:        converterandformatter.ErrIdentifierFormat,
+		},
+		{
+			name:           "individual KRA PIN",
+			identifierType: converterandformatter.IdentifierTypeKRAPIN,
+			value:          "a123456789z",
+			want:           "A123456789Z",
+		},
+		{
+			name:           "company KRA PIN",
+			identifierType: converterandformatter.IdentifierTypeKRAPIN,
+			value:          "P051234567Q",
+			want:           "P051234567Q",
+		},
+		{
+			name:           "KRA PIN without a check letter",
+			identifierType: converterandformatter.IdentifierTypeKRAPIN,
+			value:          "A1234567890",
+			wantErr:        converterandformatter.ErrIdentifierFormat,
+		},
+		{
+			name:           "KRA PIN with the wrong prefix",
+			identifierType: converterandformatter.IdentifierTypeKRAPIN,
+			value:          "B123456789Z",
+			wantErr:        converterandformatter.ErrIdentifierFormat,
+		},
+		{
+			name:           "NHIF number",
+			identifierType: converterandformatter.IdentifierTypeNHIF,
+			value:          "0123-4567",
+			want:           "01234567",
+		},
+		{
+			name:           "SHA number",
+			identifierType: converterandformatter.IdentifierTypeSHA,
+			value:          "cr1234567890123-4",
+			want:           "CR1234567890123-4",
+		},
+		{
+			name:           "SHA number without the hyphen",
+			identifierType: converterandformatter.IdentifierTypeSHA,
+			value:          "CR1234567890123 4",
+			want:           "CR1234567890123-4",
+		},
+		{
+			name:           "short SHA number",
+			identifierType: converterandformatter.IdentifierTypeSHA,
+			value:          "CR123-4",
+			wantErr:        converterandformatter.ErrIdentifierFormat,
+		},
+		{
+			name:           "alien ID",
+			identifierType: converterandformatter.IdentifierTypeAlienID,
+			value:          "123456",
+			want:           "123456",
+		},
+		{
+			name:           "birth certificate",
+			identifierType: converterandformatter.IdentifierTypeBirthCertificate,
+			value:          "123 456 789",
+			want:           "123456789",
+		},
+		{
+			name:           "empty",
+			identifierType: converterandformatter.IdentifierTypeNationalID,
+			value:          "  ",
+			wantErr:        converterandformatter.ErrIdentifierEmpty,
+		},
+		{
+			name:           "unknown type",
+			identifierType: converterandformatter.IdentifierType("DRIVING_LICENCE"),
+			value:          "12345678",
+			wantErr:        converterandformatter.ErrIdentifierType,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := converterandformatter.NormalizeIdentifier(
+				tt.identifierType, tt.value)
+			if tt.wantErr != nil {
+				assert.Nil(t, got)
+				assert.True(t, errors.Is(err, tt.wantErr))
+				var idErr *converterandformatter.IdentifierError
+				assert.True(t, errors.As(err, &idErr))
+				assert.Equal(t, tt.identifierType, idErr.Type)
+				assert.False(t, converterandformatter.IsIdentifierValid(
+					tt.identifierType, tt.value))
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, *got)
+			assert.True(t, converterandformatter.IsIdentifierValid(
+				tt.identifierType, tt.value))
+		})
+	}
+}
+
+func TestIdentifierValidators(t *testing.T) {
+	tests := []struct {
+		name      string
+		validate  func(string) error
+		normalize func(string) (*string, error)
+		value     string
+	}{
+		{
+			name:      "national ID",
+			validate:  converterandformatter.ValidateNationalID,
+			normalize: converterandformatter.NormalizeNationalID,
+			value:     "12345678",
+		},
+		{
+			name:      "passport",
+			validate:  converterandformatter.ValidatePassportNumber,
+			normalize: converterandformatter.NormalizePassportNumber,
+			value:     "AK0123456",
+		},
+		{
+			name:      "KRA PIN",
+			validate:  converterandformatter.ValidateKRAPIN,
+			normalize: converterandformatter.NormalizeKRAPIN,
+			value:     "A123456789Z",
+		},
+		{
+			name:      "NHIF",
+			validate:  converterandformatter.ValidateNHIFNumber,
+			normalize: converterandformatter.NormalizeNHIFNumber,
+			value:     "12345678",
+		},
+		{
+			name:      "SHA",
+			validate:  converterandformatter.ValidateSHANumber,
+			normalize: converterandformatter.NormalizeSHANumber,
+			value:     "CR1234567890123-4",
+		},
+		{
+			name:      "alien ID",
+			validate:  converterandformatter.ValidateAlienID,
+			normalize: converterandformatter.NormalizeAlienID,
+			value:     "1234567",
+		},
+		{
+			name:      "birth certificate",
+			validate:  converterandformatter.ValidateBirthCertificateNumber,
+			normalize: converterandformatter.NormalizeBirthCertificateNumber,
+			value:     "1234567890",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Nil(t, tt.validate(tt.value))
+			got, err := tt.normalize(tt.value)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.value, *got)
+			assert.NotNil(t, tt.validate("not an identifier"))
+		})
+	}
+}
+
+func TestIdentifierType(t *testing.T) {
+	for _, identifierType := range converterandformatter.AllIdentifierTypes {
+		assert.True(t, identifierType.IsValid())
+	}
+	assert.False(t, converterandformatter.IdentifierType("OTHER").IsValid())
+	assert.Equal(t, "KRA_PIN", converterandformatter.IdentifierTypeKRAPIN.String())
+}
+
+func TestFaker_NationalID(t *testing.T) {
+	f := converterandformatter.NewFaker(11)
+	for i := 0; i < 50; i++ {
+		assert.Nil(t, converterandformatter.ValidateNationalID(f.NationalID()))
+	}
+}
@@ -0,0 +1,106 @@
+package converterandformatter
+
+import (
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// foldCase applies full Unicode case folding so that e.g "Admin", "ADMIN"
+// and "admin" (or "Straße" and "STRASSE") compare equal
+func foldCase(s string) string {
+	return cases.Fold().String(s)
+}
+
+// normalizeForMatch puts a string in NFC form and folds its case so that
+// strings that look the same to a person compare equal
+func normalizeForMatch(s string) string {
+	return norm.NFC.String(foldCase(norm.NFC.String(s)))
+}
+
+// ContainsFold tests if a string is contained in a slice of strings ignoring
+// differences in case
+func ContainsFold(s []string, e string) bool {
+	folded := foldCase(e)
+	for _, a := range s {
+		if foldCase(a) == folded {
+			return true
+		}
+	}
+	return false
+}
+
+// ContainsNormalized tests if a string is contained in a slice of strings
+// ignoring differences in case and in Unicode composition e.g "é" written as
+// one character or as "e" followed by a combining accent
+func ContainsNormalized(s []string, e string) bool {
+	normalized := normalizeForMatch(e)
+	for _, a := range s {
+		if normalizeForMatch(a) == normalized {
+			return true
+		}
+	}
+	return false
+}
+
+// Levenshtein returns the number of single character insertions, deletions
+// and substitutions needed to turn a into b
+func Levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min3(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
+
+func min3(a, b, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
+
+// ClosestMatch returns the string in a slice that is closest to e, after
+// normalizing case and Unicode composition, provided that it is no more than
+// maxDistance edits away. It is meant for lookups that tolerate data entry
+// mistakes e.g "Nairboi" for "Nairobi".
+//
+// The first of several equally close strings is returned. The last return
+// value is false when no string is close enough.
+func ClosestMatch(s []string, e string, maxDistance int) (string, int, bool) {
+	normalized := normalizeForMatch(e)
+	best, bestDistance, found := "", 0, false
+	for _, a := range s {
+		distance := Levenshtein(normalizeForMatch(a), normalized)
+		if distance > maxDistance {
+			continue
+		}
+		if !found || distance < bestDistance {
+			best, bestDistance, found = a, distance, true
+		}
+	}
+	return best, bestDistance, found
+}
+
+// ContainsFuzzy tests if a slice of strings contains a string that is no
+// more than maxDistance edits away from e, ignoring case and Unicode
+// composition
+func ContainsFuzzy(s []string, e string, maxDistance int) bool {
+	_, _, found := ClosestMatch(s, e, maxDistance)
+	return found
+}
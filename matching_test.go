@@ -0,0 +1,65 @@
+package converterandformatter_test
+
+import (
+	"testing"
+
+	"github.com/savannahghi/converterandformatter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContainsFold(t *testing.T) {
+	roles := []string{"Admin", "Editor", "Straße"}
+	assert.True(t, converterandformatter.ContainsFold(roles, "admin"))
+	assert.True(t, converterandformatter.ContainsFold(roles, "EDITOR"))
+	assert.True(t, converterandformatter.ContainsFold(roles, "STRASSE"))
+	assert.False(t, converterandformatter.ContainsFold(roles, "viewer"))
+}
+
+func TestContainsNormalized(t *testing.T) {
+	composed := "Caf\u00e9"
+	decomposed := "Cafe\u0301"
+	assert.False(t, converterandformatter.ContainsFold([]string{composed}, decomposed))
+	assert.True(t, converterandformatter.ContainsNormalized([]string{composed}, decomposed))
+	assert.True(t, converterandformatter.ContainsNormalized([]string{"CAFÉ"}, decomposed))
+	assert.False(t, converterandformatter.ContainsNormalized([]string{"Cafe"}, decomposed))
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "", b: "", want: 0},
+		{a: "", b: "abc", want: 3},
+		{a: "kitten", b: "sitting", want: 3},
+		{a: "Nairobi", b: "Nairboi", want: 2},
+		{a: "Mombasa", b: "Mombasa", want: 0},
+		{a: "café", b: "cafe", want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			assert.Equal(t, tt.want, converterandformatter.Levenshtein(tt.a, tt.b))
+			assert.Equal(t, tt.want, converterandformatter.Levenshtein(tt.b, tt.a))
+		})
+	}
+}
+
+func TestClosestMatch(t *testing.T) {
+	towns := []string{"Nairobi", "Nakuru", "Naivasha", "Kisumu"}
+
+	got, distance, ok := converterandformatter.ClosestMatch(towns, "nairboi", 2)
+	assert.True(t, ok)
+	assert.Equal(t, "Nairobi", got)
+	assert.Equal(t, 2, distance)
+
+	got, distance, ok = converterandformatter.ClosestMatch(towns, "NAKURU", 0)
+	assert.True(t, ok)
+	assert.Equal(t, "Nakuru", got)
+	assert.Equal(t, 0, distance)
+
+	_, _, ok = converterandformatter.ClosestMatch(towns, "Mombasa", 2)
+	assert.False(t, ok)
+
+	assert.True(t, converterandformatter.ContainsFuzzy(towns, "Kisum", 1))
+	assert.False(t, converterandformatter.ContainsFuzzy(towns, "Kisum", 0))
+}
@@ -1,5 +1,7 @@
 package converterandformatter
 
+import "time"
+
 // USSDSessionLog is used to persist a log of USSD sessions
 type USSDSessionLog struct {
 	MSISDN    string `json:"msisdn" firestore:"msisdn"`
@@ -17,3 +19,123 @@ type PhoneOptIn struct {
 
 //IsEntity ...
 func (p USSDSessionLog) IsEntity() {}
+
+// OptInChannel is the medium over which a communication is delivered
+type OptInChannel string
+
+// channels that a phone number can opt in to or out of
+const (
+	OptInChannelSMS      OptInChannel = "SMS"
+	OptInChannelWhatsApp OptInChannel = "WHATSAPP"
+	OptInChannelVoice    OptInChannel = "VOICE"
+)
+
+// AllOptInChannels is a list of all the known opt in channels
+var AllOptInChannels = []OptInChannel{
+	OptInChannelSMS,
+	OptInChannelWhatsApp,
+	OptInChannelVoice,
+}
+
+// IsValid returns true if the channel is a known opt in channel
+func (c OptInChannel) IsValid() bool {
+	switch c {
+	case OptInChannelSMS, OptInChannelWhatsApp, OptInChannelVoice:
+		return true
+	}
+	return false
+}
+
+func (c OptInChannel) String() string {
+	return string(c)
+}
+
+// OptInTopic is the subject matter of a communication
+type OptInTopic string
+
+// topics that a phone number can opt in to or out of
+const (
+	OptInTopicAppointments OptInTopic = "APPOINTMENTS"
+	OptInTopicMarketing    OptInTopic = "MARKETING"
+	OptInTopicHealthTips   OptInTopic = "HEALTH_TIPS"
+)
+
+// AllOptInTopics is a list of all the known opt in topics
+var AllOptInTopics = []OptInTopic{
+	OptInTopicAppointments,
+	OptInTopicMarketing,
+	OptInTopicHealthTips,
+}
+
+// IsValid returns true if the topic is a known opt in topic
+func (t OptInTopic) IsValid() bool {
+	switch t {
+	case OptInTopicAppointments, OptInTopicMarketing, OptInTopicHealthTips:
+		return true
+	}
+	return false
+}
+
+func (t OptInTopic) String() string {
+	return string(t)
+}
+
+// PhoneOptInPreference records whether a phone number consents to receive
+// communication about a single topic over a single channel
+type PhoneOptInPreference struct {
+	MSISDN    string       `json:"msisdn" firestore:"msisdn"`
+	Channel   OptInChannel `json:"channel" firestore:"channel"`
+	Topic     OptInTopic   `json:"topic" firestore:"topic"`
+	OptedIn   bool         `json:"optedIn" firestore:"optedIn"`
+	UpdatedAt time.Time    `json:"updatedAt" firestore:"updatedAt"`
+
+	// OptedOutByKeyword is true when consent was withdrawn by an SMS opt out
+	// keyword (e.g STOP), so that an opt in keyword (e.g START) restores it
+	OptedOutByKeyword bool `json:"optedOutByKeyword" firestore:"optedOutByKeyword"`
+}
+
+// IsEntity ...
+func (p PhoneOptInPreference) IsEntity() {}
+
+// USSDSessionStatus is the lifecycle state of a USSD session
+type USSDSessionStatus string
+
+// the states that a USSD session moves through
+const (
+	USSDSessionStatusActive    USSDSessionStatus = "ACTIVE"
+	USSDSessionStatusCompleted USSDSessionStatus = "COMPLETED"
+	USSDSessionStatusTimedOut  USSDSessionStatus = "TIMED_OUT"
+	USSDSessionStatusFailed    USSDSessionStatus = "FAILED"
+)
+
+// IsValid returns true if the status is a known USSD session status
+func (s USSDSessionStatus) IsValid() bool {
+	switch s {
+	case USSDSessionStatusActive, USSDSessionStatusCompleted,
+		USSDSessionStatusTimedOut, USSDSessionStatusFailed:
+		return true
+	}
+	return false
+}
+
+func (s USSDSessionStatus) String() string {
+	return string(s)
+}
+
+// USSDSession is used to persist and track a USSD session from the first dial
+// to the final screen
+type USSDSession struct {
+	SessionID   string            `json:"sessionID" firestore:"sessionID"`
+	MSISDN      string            `json:"msisdn" firestore:"msisdn"`
+	ServiceCode string            `json:"serviceCode" firestore:"serviceCode"`
+	NetworkCode string            `json:"networkCode" firestore:"networkCode"`
+	MenuState   string            `json:"menuState" firestore:"menuState"`
+	Inputs      []string          `json:"inputs" firestore:"inputs"`
+	Status      USSDSessionStatus `json:"status" firestore:"status"`
+	StartedAt   time.Time         `json:"startedAt" firestore:"startedAt"`
+	UpdatedAt   time.Time         `json:"updatedAt" firestore:"updatedAt"`
+	EndedAt     *time.Time        `json:"endedAt,omitempty" firestore:"endedAt,omitempty"`
+}
+
+// IsEntity ...
+func (s USSDSession) IsEntity() {}
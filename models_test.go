@@ -13,4 +13,10 @@ func TestModelsIsEntity(t *testing.T) {
 
 	t13 := converterandformatter.PhoneOptIn{}
 	t13.IsEntity()
+
+	t14 := converterandformatter.PhoneOptInPreference{}
+	t14.IsEntity()
+
+	t15 := converterandformatter.USSDSession{}
+	t15.IsEntity()
 }
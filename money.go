@@ -0,0 +1,354 @@
+package converterandformatter
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Currency is an ISO 4217 currency code
+type Currency string
+
+// the currencies that money can be formatted and parsed in
+const (
+	CurrencyKES Currency = "KES"
+	CurrencyUGX Currency = "UGX"
+	CurrencyTZS Currency = "TZS"
+	CurrencyRWF Currency = "RWF"
+	CurrencyUSD Currency = "USD"
+)
+
+// AllCurrencies is a list of all the known currencies
+var AllCurrencies = []Currency{
+	CurrencyKES,
+	CurrencyUGX,
+	CurrencyTZS,
+	CurrencyRWF,
+	CurrencyUSD,
+}
+
+// IsValid returns true if the currency is a known currency
+func (c Currency) IsValid() bool {
+	_, ok := currencyFormats[c]
+	return ok
+}
+
+func (c Currency) String() string {
+	return string(c)
+}
+
+// MinorUnits returns the number of digits after the decimal point in an
+// amount of the currency e.g 2 for KES and 0 for UGX
+func (c Currency) MinorUnits() int {
+	return currencyFormats[c].minorUnits
+}
+
+// currencyFormat describes how amounts in a currency are written
+type currencyFormat struct {
+	minorUnits int
+	// symbol is the local symbol e.g KSh, and symbolSpace is true if it is
+	// separated from the amount by a space
+	symbol      string
+	symbolSpace bool
+	// aliases are the (upper case) ways people write the currency, other than
+	// its code, when entering amounts
+	aliases []string
+}
+
+var currencyFormats = map[Currency]currencyFormat{
+	CurrencyKES: {
+		minorUnits:  2,
+		symbol:      "KSh",
+		symbolSpace: true,
+		aliases:     []string{"KSH", "KSH.", "KSHS", "KSHS."},
+	},
+	CurrencyUGX: {
+		minorUnits:  0,
+		symbol:      "USh",
+		symbolSpace: true,
+		aliases:     []string{"USH", "USH.", "USHS", "USHS."},
+	},
+	CurrencyTZS: {
+		minorUnits:  2,
+		symbol:      "TSh",
+		symbolSpace: true,
+		aliases:     []string{"TSH", "TSH.", "TSHS", "TSHS."},
+	},
+	CurrencyRWF: {
+		minorUnits:  0,
+		symbol:      "FRw",
+		symbolSpace: true,
+		aliases:     []string{"FRW", "RF", "FRS"},
+	},
+	CurrencyUSD: {
+		minorUnits:  2,
+		symbol:      "$",
+		symbolSpace: false,
+		aliases:     []string{"US$", "$"},
+	},
+}
+
+// shillingAliases are written for every shilling, so they are read as the
+// default currency when it is a shilling and as Kenya shillings otherwise
+var shillingAliases = []string{"SH", "SH.", "SHS", "SHS."}
+
+// Money is an amount of a currency held as an integer number of the
+// currency's minor units (e.g cents) so that no float rounding creeps in
+type Money struct {
+	Amount   int64    `json:"amount" firestore:"amount"`
+	Currency Currency `json:"currency" firestore:"currency"`
+}
+
+// Add returns the sum of two amounts of the same currency
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, fmt.Errorf(
+			"unable to add %s to %s: currencies differ", other.Currency, m.Currency)
+	}
+	if (other.Amount > 0 && m.Amount > math.MaxInt64-other.Amount) ||
+		(other.Amount < 0 && m.Amount < math.MinInt64-other.Amount) {
+		return Money{}, fmt.Errorf("unable to add %s to %s: amount out of range", other, m)
+	}
+	return Money{Amount: m.Amount + other.Amount, Currency: m.Currency}, nil
+}
+
+// Sub returns the difference of two amounts of the same currency
+func (m Money) Sub(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, fmt.Errorf(
+			"unable to subtract %s from %s: currencies differ", other.Currency, m.Currency)
+	}
+	if (other.Amount < 0 && m.Amount > math.MaxInt64+other.Amount) ||
+		(other.Amount > 0 && m.Amount < math.MinInt64+other.Amount) {
+		return Money{}, fmt.Errorf("unable to subtract %s from %s: amount out of range", other, m)
+	}
+	return Money{Amount: m.Amount - other.Amount, Currency: m.Currency}, nil
+}
+
+// IsZero returns true if the amount is zero
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+func (m Money) String() string {
+	return FormatMoney(m)
+}
+
+// MoneyFormatOption changes how FormatMoney writes an amount and, for the
+// digit separators, how ParseMoney reads one
+type MoneyFormatOption func(*moneyFormatConfig)
+
+type moneyFormatConfig struct {
+	symbol           bool
+	groupSeparator   string
+	decimalSeparator string
+}
+
+// UseCurrencySymbol writes the currency's local symbol e.g "KSh 1,234.50"
+// instead of its code
+func UseCurrencySymbol() MoneyFormatOption {
+	return func(c *moneyFormatConfig) {
+		c.symbol = true
+	}
+}
+
+// WithDigitSeparators writes and reads amounts with the thousands and
+// decimal separators of a locale other than East African English and Swahili
+// e.g WithDigitSeparators(".", ",") for "RWF 25.000" or "KES 1.234,50". An
+// empty group separator leaves the thousands ungrouped.
+func WithDigitSeparators(group, decimal string) MoneyFormatOption {
+	return func(c *moneyFormatConfig) {
+		c.groupSeparator = group
+		c.decimalSeparator = decimal
+	}
+}
+
+// FormatMoney writes an amount with its currency code and as many decimal
+// places as the currency has minor units. Thousands are separated by commas
+// and decimals by a point, as is usual in East Africa, e.g "KES 1,234.50" or
+// "UGX 25,000" unless WithDigitSeparators is used.
+func FormatMoney(m Money, opts ...MoneyFormatOption) string {
+	config := moneyFormatConfig{groupSeparator: ",", decimalSeparator: "."}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	format, ok := currencyFormats[m.Currency]
+	if !ok {
+		format = currencyFormat{minorUnits: 2, symbol: m.Currency.String()}
+	}
+
+	amount := strconv.FormatInt(m.Amount, 10)
+	sign := ""
+	if strings.HasPrefix(amount, "-") {
+		sign, amount = "-", amount[1:]
+	}
+	if len(amount) <= format.minorUnits {
+		amount = strings.Repeat("0", format.minorUnits-len(amount)+1) + amount
+	}
+	major := amount[:len(amount)-format.minorUnits]
+	minor := amount[len(amount)-format.minorUnits:]
+
+	var b strings.Builder
+	for i, digit := range major {
+		if i > 0 && (len(major)-i)%3 == 0 {
+			b.WriteString(config.groupSeparator)
+		}
+		b.WriteRune(digit)
+	}
+	if minor != "" {
+		b.WriteString(config.decimalSeparator + minor)
+	}
+
+	if config.symbol {
+		if format.symbolSpace {
+			return sign + format.symbol + " " + b.String()
+		}
+		return sign + format.symbol + b.String()
+	}
+	return sign + m.Currency.String() + " " + b.String()
+}
+
+// ParseMoney reads an amount as people write it e.g "KES 1,234.50",
+// "Ksh 1,000/=", "1000 shs" or "-$12.5".
+//
+// The currency is recognized from its code, symbol or common abbreviations
+// written before or after the amount. When none is written the amount is in
+// the default currency.
+//
+// Thousands are separated by commas and decimals by a point unless
+// WithDigitSeparators is used. An amount such as "UGX 1.000", whose decimal
+// point could be a thousands separator in a currency without minor units, is
+// rejected rather than read as UGX 1.
+func ParseMoney(
+	input string, defaultCurrency Currency, opts ...MoneyFormatOption) (Money, error) {
+	config := moneyFormatConfig{groupSeparator: ",", decimalSeparator: "."}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	fail := func(reason string) (Money, error) {
+		return Money{}, fmt.Errorf("invalid amount %q: %s", input, reason)
+	}
+
+	text := strings.ToUpper(strings.TrimSpace(input))
+	negative := false
+	if strings.HasPrefix(text, "-") {
+		negative, text = true, strings.TrimSpace(text[1:])
+	}
+	for _, suffix := range []string{"/=", "/-", "="} {
+		text = strings.TrimSpace(strings.TrimSuffix(text, suffix))
+	}
+
+	currency, text := parseCurrency(text, defaultCurrency)
+	if !currency.IsValid() {
+		return fail("unknown currency")
+	}
+	if strings.HasPrefix(text, "-") && !negative {
+		negative, text = true, strings.TrimSpace(text[1:])
+	}
+
+	amount, err := parseMinorUnits(text, currency.MinorUnits(), config)
+	if err != nil {
+		return fail(err.Error())
+	}
+	if negative {
+		amount = -amount
+	}
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+// currencyMarker is a way of writing a currency in an amount
+type currencyMarker struct {
+	text     string
+	currency Currency
+}
+
+// currencyMarkers returns the ways of writing each currency, longest first
+// so that e.g "KSHS" is matched before "SH"
+func currencyMarkers(defaultCurrency Currency) []currencyMarker {
+	shilling := CurrencyKES
+	if defaultCurrency == CurrencyUGX || defaultCurrency == CurrencyTZS {
+		shilling = defaultCurrency
+	}
+	markers := []currencyMarker{}
+	for _, currency := range AllCurrencies {
+		markers = append(markers, currencyMarker{currency.String(), currency})
+		for _, alias := range currencyFormats[currency].aliases {
+			markers = append(markers, currencyMarker{alias, currency})
+		}
+	}
+	for _, alias := range shillingAliases {
+		markers = append(markers, currencyMarker{alias, shilling})
+	}
+	sort.SliceStable(markers, func(i, j int) bool {
+		return len(markers[i].text) > len(markers[j].text)
+	})
+	return markers
+}
+
+// parseCurrency finds the currency written before or after an amount and
+// returns it along with the rest of the text
+func parseCurrency(text string, defaultCurrency Currency) (Currency, string) {
+	for _, marker := range currencyMarkers(defaultCurrency) {
+		if strings.HasPrefix(text, marker.text) {
+			return marker.currency, strings.TrimSpace(text[len(marker.text):])
+		}
+		if strings.HasSuffix(text, marker.text) {
+			return marker.currency, strings.TrimSpace(text[:len(text)-len(marker.text)])
+		}
+	}
+	return defaultCurrency, text
+}
+
+// parseMinorUnits reads a decimal number, with optional thousands
+// separators, as an integer number of minor units
+func parseMinorUnits(text string, minorUnits int, config moneyFormatConfig) (int64, error) {
+	if text == "" {
+		return 0, fmt.Errorf("no amount")
+	}
+	major, minor := text, ""
+	if config.decimalSeparator != "" {
+		if i := strings.Index(text, config.decimalSeparator); i >= 0 {
+			major, minor = text[:i], text[i+len(config.decimalSeparator):]
+		}
+	}
+	if minorUnits == 0 && len(minor) == 3 {
+		return 0, fmt.Errorf(
+			"%q followed by three digits looks like a thousands separator",
+			config.decimalSeparator)
+	}
+	if config.groupSeparator != "" && strings.Contains(major, config.groupSeparator) {
+		groups := strings.Split(major, config.groupSeparator)
+		for i, group := range groups {
+			if (i == 0 && (len(group) == 0 || len(group) > 3)) ||
+				(i > 0 && len(group) != 3) {
+				return 0, fmt.Errorf("misplaced thousands separator")
+			}
+		}
+		major = strings.Join(groups, "")
+	}
+	if major == "" {
+		major = "0"
+	}
+	if len(minor) > minorUnits {
+		// trailing zeros beyond the currency's precision do not change the
+		// amount e.g UGX 1,000.00
+		trimmed := strings.TrimRight(minor[minorUnits:], "0")
+		if trimmed != "" {
+			return 0, fmt.Errorf("more than %d decimal places", minorUnits)
+		}
+		minor = minor[:minorUnits]
+	}
+	minor += strings.Repeat("0", minorUnits-len(minor))
+	for _, r := range major + minor {
+		if r < '0' || r > '9' {
+			return 0, fmt.Errorf("unexpected character %q", r)
+		}
+	}
+	amount, err := strconv.ParseInt(major+minor, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("amount out of range")
+	}
+	return amount, nil
+}
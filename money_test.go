@@ -0,0 +1,282 @@
+package converterandformatter_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/savannahghi/converterandformatter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatMoney(t *testing.T) {
+	tests := []struct {
+		name  string
+		money converterandformatter.Money
+		opts  []converterandformatter.MoneyFormatOption
+		want  string
+	}{
+		{
+			name:  "shillings and cents",
+			money: converterandformatter.Money{Amount: 123450, Currency: converterandformatter.CurrencyKES},
+			want:  "KES 1,234.50",
+		},
+		{
+			name:  "cents only",
+			money: converterandformatter.Money{Amount: 5, Currency: converterandformatter.CurrencyKES},
+			want:  "KES 0.05",
+		},
+		{
+			name:  "zero",
+			money: converterandformatter.Money{Currency: converterandformatter.CurrencyKES},
+			want:  "KES 0.00",
+		},
+		{
+			name:  "negative",
+			money: converterandformatter.Money{Amount: -100000050, Currency: converterandformatter.CurrencyKES},
+			want:  "-KES 1,000,000.50",
+		},
+		{
+			name:  "no minor units",
+			money: converterandformatter.Money{Amount: 25000, Currency: converterandformatter.CurrencyUGX},
+			want:  "UGX 25,000",
+		},
+		{
+			name:  "Kenya shilling symbol",
+			money: converterandformatter.Money{Amount: 100000, Currency: converterandformatter.CurrencyKES},
+			opts:  []converterandformatter.MoneyFormatOption{converterandformatter.UseCurrencySymbol()},
+			want:  "KSh 1,000.00",
+		},
+		{
+			name:  "Rwanda franc symbol",
+			money: converterandformatter.Money{Amount: 1500, Currency: converterandformatter.CurrencyRWF},
+			opts:  []converterandformatter.MoneyFormatOption{converterandformatter.UseCurrencySymbol()},
+			want:  "FRw 1,500",
+		},
+		{
+			name:  "dollar symbol",
+			money: converterandformatter.Money{Amount: -1250, Currency: converterandformatter.CurrencyUSD},
+			opts:  []converterandformatter.MoneyFormatOption{converterandformatter.UseCurrencySymbol()},
+			want:  "-$12.50",
+		},
+		{
+			name:  "point grouping and comma decimals",
+			money: converterandformatter.Money{Amount: 123450, Currency: converterandformatter.CurrencyKES},
+			opts:  []converterandformatter.MoneyFormatOption{converterandformatter.WithDigitSeparators(".", ",")},
+			want:  "KES 1.234,50",
+		},
+		{
+			name:  "space grouping with a symbol",
+			money: converterandformatter.Money{Amount: 2500000, Currency: converterandformatter.CurrencyRWF},
+			opts: []converterandformatter.MoneyFormatOption{
+				converterandformatter.UseCurrencySymbol(),
+				converterandformatter.WithDigitSeparators(" ", ","),
+			},
+			want: "FRw 2 500 000",
+		},
+		{
+			name:  "no grouping",
+			money: converterandformatter.Money{Amount: 100000050, Currency: converterandformatter.CurrencyKES},
+			opts:  []converterandformatter.MoneyFormatOption{converterandformatter.WithDigitSeparators("", ".")},
+			want:  "KES 1000000.50",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, converterandformatter.FormatMoney(tt.money, tt.opts...))
+		})
+	}
+}
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		name            string
+		input           string
+		defaultCurrency converterandformatter.Currency
+		want            converterandformatter.Money
+		wantErr         bool
+	}{
+		{
+			name:  "formatted amount",
+			input: "KES 1,234.50",
+			want:  converterandformatter.Money{Amount: 123450, Currency: converterandformatter.CurrencyKES},
+		},
+		{
+			name:  "Ksh with /=",
+			input: "Ksh 1,000/=",
+			want:  converterandformatter.Money{Amount: 100000, Currency: converterandformatter.CurrencyKES},
+		},
+		{
+			name:  "Kshs. without a space",
+			input: "Kshs.500",
+			want:  converterandformatter.Money{Amount: 50000, Currency: converterandformatter.CurrencyKES},
+		},
+		{
+			name:  "currency after the amount",
+			input: "2,500 shs",
+			want:  converterandformatter.Money{Amount: 250000, Currency: converterandformatter.CurrencyKES},
+		},
+		{
+			name:            "shillings in the default currency",
+			input:           "Shs 2,500",
+			defaultCurrency: converterandformatter.CurrencyUGX,
+			want:            converterandformatter.Money{Amount: 2500, Currency: converterandformatter.CurrencyUGX},
+		},
+		{
+			name:            "no currency",
+			input:           "1234.5",
+			defaultCurrency: converterandformatter.CurrencyKES,
+			want:            converterandformatter.Money{Amount: 123450, Currency: converterandformatter.CurrencyKES},
+		},
+		{
+			name:  "negative dollars",
+			input: "-$12.5",
+			want:  converterandformatter.Money{Amount: -1250, Currency: converterandformatter.CurrencyUSD},
+		},
+		{
+			name:  "Rwanda francs",
+			input: "1,500 FRW",
+			want:  converterandformatter.Money{Amount: 1500, Currency: converterandformatter.CurrencyRWF},
+		},
+		{
+			name:  "zero cents on a currency without minor units",
+			input: "UGX 1,000.00",
+			want:  converterandformatter.Money{Amount: 1000, Currency: converterandformatter.CurrencyUGX},
+		},
+		{
+			name:    "too many decimal places",
+			input:   "KES 10.005",
+			wantErr: true,
+		},
+		{
+			name:    "cents on a currency without minor units",
+			input:   "UGX 10.5",
+			wantErr: true,
+		},
+		{
+			name:    "misplaced thousands separator",
+			input:   "KES 1,00",
+			wantErr: true,
+		},
+		{
+			name:    "not a number",
+			input:   "KES ten",
+			wantErr: true,
+		},
+		{
+			name:    "no amount",
+			input:   "KES",
+			wantErr: true,
+		},
+		{
+			name:    "no currency or default",
+			input:   "100",
+			wantErr: true,
+		},
+		{
+			name:    "out of range",
+			input:   "KES 999,999,999,999,999,999",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := converterandformatter.ParseMoney(tt.input, tt.defaultCurrency)
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseMoney_RoundTrip(t *testing.T) {
+	for _, currency := range converterandformatter.AllCurrencies {
+		t.Run(currency.String(), func(t *testing.T) {
+			assert.True(t, currency.IsValid())
+			money := converterandformatter.Money{Amount: -123456789, Currency: currency}
+			for _, formatted := range []string{
+				converterandformatter.FormatMoney(money),
+				converterandformatter.FormatMoney(money, converterandformatter.UseCurrencySymbol()),
+			} {
+				got, err := converterandformatter.ParseMoney(formatted, "")
+				assert.Nil(t, err)
+				assert.Equal(t, money, got, formatted)
+			}
+		})
+	}
+}
+
+func TestParseMoney_DigitSeparators(t *testing.T) {
+	separators := [][2]string{{".", ","}, {" ", ","}, {"", "."}}
+	for _, currency := range converterandformatter.AllCurrencies {
+		for _, sep := range separators {
+			t.Run(currency.String()+" "+sep[0]+sep[1], func(t *testing.T) {
+				opt := converterandformatter.WithDigitSeparators(sep[0], sep[1])
+				money := converterandformatter.Money{Amount: 2512345678, Currency: currency}
+				formatted := converterandformatter.FormatMoney(money, opt)
+				got, err := converterandformatter.ParseMoney(formatted, "", opt)
+				assert.Nil(t, err)
+				assert.Equal(t, money, got, formatted)
+			})
+		}
+	}
+
+	got, err := converterandformatter.ParseMoney(
+		"KES 1.234,50", converterandformatter.CurrencyKES,
+		converterandformatter.WithDigitSeparators(".", ","))
+	assert.Nil(t, err)
+	assert.Equal(t, int64(123450), got.Amount)
+
+	// a point followed by three digits is not read as a decimal point in a
+	// currency without minor units
+	for _, input := range []string{"RWF 25.000", "UGX 1.000"} {
+		_, err = converterandformatter.ParseMoney(input, converterandformatter.CurrencyKES)
+		assert.NotNil(t, err, input)
+	}
+	_, err = converterandformatter.ParseMoney("KES 1.234,50", converterandformatter.CurrencyKES)
+	assert.NotNil(t, err)
+}
+
+func TestMoney_Arithmetic(t *testing.T) {
+	a := converterandformatter.Money{Amount: 150, Currency: converterandformatter.CurrencyKES}
+	b := converterandformatter.Money{Amount: 50, Currency: converterandformatter.CurrencyKES}
+
+	sum, err := a.Add(b)
+	assert.Nil(t, err)
+	assert.Equal(t, "KES 2.00", sum.String())
+
+	diff, err := b.Sub(a)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(-100), diff.Amount)
+	assert.False(t, diff.IsZero())
+
+	dollars := converterandformatter.Money{Amount: 50, Currency: converterandformatter.CurrencyUSD}
+	_, err = a.Add(dollars)
+	assert.NotNil(t, err)
+	_, err = a.Sub(dollars)
+	assert.NotNil(t, err)
+
+	largest := converterandformatter.Money{Amount: math.MaxInt64, Currency: converterandformatter.CurrencyKES}
+	smallest := converterandformatter.Money{Amount: math.MinInt64, Currency: converterandformatter.CurrencyKES}
+	one := converterandformatter.Money{Amount: 1, Currency: converterandformatter.CurrencyKES}
+	minusOne := converterandformatter.Money{Amount: -1, Currency: converterandformatter.CurrencyKES}
+	_, err = largest.Add(one)
+	assert.NotNil(t, err)
+	_, err = smallest.Add(minusOne)
+	assert.NotNil(t, err)
+	_, err = smallest.Sub(one)
+	assert.NotNil(t, err)
+	_, err = largest.Sub(minusOne)
+	assert.NotNil(t, err)
+	_, err = b.Sub(smallest)
+	assert.NotNil(t, err)
+
+	sum, err = largest.Add(minusOne)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(math.MaxInt64-1), sum.Amount)
+	diff, err = smallest.Sub(minusOne)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(math.MinInt64+1), diff.Amount)
+}
@@ -0,0 +1,342 @@
+package converterandformatter
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// MPesaTransactionType is the direction of an M-Pesa transaction as seen by
+// the account holder
+type MPesaTransactionType string
+
+// the kinds of M-Pesa transaction that can be parsed
+const (
+	MPesaTransactionTypeReceived  MPesaTransactionType = "RECEIVED"
+	MPesaTransactionTypeSent      MPesaTransactionType = "SENT"
+	MPesaTransactionTypePaid      MPesaTransactionType = "PAID"
+	MPesaTransactionTypeWithdrawn MPesaTransactionType = "WITHDRAWN"
+)
+
+// AllMPesaTransactionTypes is a list of all the known M-Pesa transaction types
+var AllMPesaTransactionTypes = []MPesaTransactionType{
+	MPesaTransactionTypeReceived,
+	MPesaTransactionTypeSent,
+	MPesaTransactionTypePaid,
+	MPesaTransactionTypeWithdrawn,
+}
+
+// IsValid returns true if the transaction type is known
+func (t MPesaTransactionType) IsValid() bool {
+	switch t {
+	case MPesaTransactionTypeReceived, MPesaTransactionTypeSent,
+		MPesaTransactionTypePaid, MPesaTransactionTypeWithdrawn:
+		return true
+	}
+	return false
+}
+
+func (t MPesaTransactionType) String() string {
+	return string(t)
+}
+
+// MPesaTransaction is an M-Pesa transaction read from a confirmation SMS or
+// a Daraja API callback
+type MPesaTransaction struct {
+	ReceiptCode string               `json:"receiptCode" firestore:"receiptCode"`
+	Type        MPesaTransactionType `json:"type" firestore:"type"`
+	Amount      Money                `json:"amount" firestore:"amount"`
+
+	// the other party's name e.g JOHN KAMAU or KPLC PREPAID, and, when the
+	// other party is a person with a readable phone number, their
+	// normalized MSISDN
+	CounterpartyName   string `json:"counterpartyName,omitempty" firestore:"counterpartyName,omitempty"`
+	CounterpartyMSISDN string `json:"counterpartyMSISDN,omitempty" firestore:"counterpartyMSISDN,omitempty"`
+
+	// the paybill account number or agent number, when there is one
+	AccountNumber string `json:"accountNumber,omitempty" firestore:"accountNumber,omitempty"`
+
+	TransactionTime time.Time `json:"transactionTime" firestore:"transactionTime"`
+	Balance         *Money    `json:"balance,omitempty" firestore:"balance,omitempty"`
+	TransactionCost *Money    `json:"transactionCost,omitempty" firestore:"transactionCost,omitempty"`
+}
+
+// an M-Pesa receipt code is ten upper case letters and digits that start
+// with a letter e.g QGH7XK2L9P
+var (
+	mpesaReceiptPattern = regexp.MustCompile(`^[A-Z][A-Z0-9]{9}$`)
+	mpesaDigitPattern   = regexp.MustCompile(`[0-9]`)
+)
+
+// IsMPesaReceiptCodeValid checks that a value is a well formed M-Pesa
+// receipt code
+func IsMPesaReceiptCodeValid(code string) bool {
+	// every receipt code has a digit, which rules out ten letter words
+	return mpesaReceiptPattern.MatchString(code) &&
+		mpesaDigitPattern.MatchString(code)
+}
+
+// patterns for the parts of an M-Pesa confirmation SMS. The message is
+// upper cased and its whitespace collapsed before they are applied.
+const mpesaAmount = `KSH\s?[0-9,]+(?:\.[0-9]{1,2})?`
+
+var (
+	mpesaSMSReceipt = regexp.MustCompile(`^([A-Z0-9]{10})\s?CONFIRMED`)
+	mpesaSMSTime    = regexp.MustCompile(
+		`ON ([0-9]{1,2}/[0-9]{1,2}/[0-9]{2,4}) AT ([0-9]{1,2}:[0-9]{2})\s?([AP]M)`)
+	mpesaSMSBalance = regexp.MustCompile(`M-PESA BALANCE IS (` + mpesaAmount + `)`)
+	mpesaSMSCost    = regexp.MustCompile(`TRANSACTION COST,? (` + mpesaAmount + `)`)
+
+	mpesaSMSReceived = regexp.MustCompile(
+		`YOU HAVE RECEIVED (` + mpesaAmount + `) FROM (.+?)\.? ON [0-9]`)
+	mpesaSMSPaybill = regexp.MustCompile(
+		`(` + mpesaAmount + `) SENT TO (.+?) FOR ACCOUNT (.+?) ON [0-9]`)
+	mpesaSMSSent = regexp.MustCompile(
+		`(` + mpesaAmount + `) SENT TO (.+?)\.? ON [0-9]`)
+	mpesaSMSPaid = regexp.MustCompile(
+		`(` + mpesaAmount + `) PAID TO (.+?)\.? ON [0-9]`)
+	mpesaSMSWithdrawn = regexp.MustCompile(
+		`WITHDRAW (` + mpesaAmount + `) FROM ([0-9]+) - (.+?) NEW M-PESA`)
+)
+
+// ParseMPesaSMS reads an M-Pesa confirmation SMS for money received, sent,
+// paid to a till or paybill, or withdrawn at an agent
+func ParseMPesaSMS(message string) (*MPesaTransaction, error) {
+	text := strings.ToUpper(strings.Join(strings.Fields(message), " "))
+
+	receipt := mpesaSMSReceipt.FindStringSubmatch(text)
+	if receipt == nil || !IsMPesaReceiptCodeValid(receipt[1]) {
+		return nil, fmt.Errorf("the message is not an M-Pesa confirmation")
+	}
+	transaction := &MPesaTransaction{ReceiptCode: receipt[1]}
+
+	var amount string
+	if m := mpesaSMSReceived.FindStringSubmatch(text); m != nil {
+		transaction.Type = MPesaTransactionTypeReceived
+		amount = m[1]
+		transaction.CounterpartyName, transaction.CounterpartyMSISDN = splitMPesaCounterparty(m[2])
+	} else if m := mpesaSMSPaybill.FindStringSubmatch(text); m != nil {
+		transaction.Type = MPesaTransactionTypePaid
+		amount, transaction.CounterpartyName, transaction.AccountNumber = m[1], m[2], m[3]
+	} else if m := mpesaSMSSent.FindStringSubmatch(text); m != nil {
+		transaction.Type = MPesaTransactionTypeSent
+		amount = m[1]
+		transaction.CounterpartyName, transaction.CounterpartyMSISDN = splitMPesaCounterparty(m[2])
+	} else if m := mpesaSMSPaid.FindStringSubmatch(text); m != nil {
+		transaction.Type = MPesaTransactionTypePaid
+		amount, transaction.CounterpartyName = m[1], m[2]
+	} else if m := mpesaSMSWithdrawn.FindStringSubmatch(text); m != nil {
+		transaction.Type = MPesaTransactionTypeWithdrawn
+		amount, transaction.AccountNumber, transaction.CounterpartyName = m[1], m[2], m[3]
+	} else {
+		return nil, fmt.Errorf("unknown M-Pesa message template")
+	}
+
+	money, err := ParseMoney(amount, CurrencyKES)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the transaction amount: %v", err)
+	}
+	transaction.Amount = money
+
+	when := mpesaSMSTime.FindStringSubmatch(text)
+	if when == nil {
+		return nil, fmt.Errorf("the message has no transaction time")
+	}
+	transaction.TransactionTime, err = parseMPesaSMSTime(when[1], when[2]+" "+when[3])
+	if err != nil {
+		return nil, err
+	}
+
+	if m := mpesaSMSBalance.FindStringSubmatch(text); m != nil {
+		balance, err := ParseMoney(m[1], CurrencyKES)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse the balance: %v", err)
+		}
+		transaction.Balance = &balance
+	}
+	if m := mpesaSMSCost.FindStringSubmatch(text); m != nil {
+		cost, err := ParseMoney(m[1], CurrencyKES)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse the transaction cost: %v", err)
+		}
+		transaction.TransactionCost = &cost
+	}
+	return transaction, nil
+}
+
+// splitMPesaCounterparty separates a trailing phone number from the name of
+// the person that money was sent to or received from. Masked numbers
+// (e.g 0722***456) are dropped.
+func splitMPesaCounterparty(counterparty string) (string, string) {
+	fields := strings.Fields(counterparty)
+	if len(fields) < 2 {
+		return counterparty, ""
+	}
+	last := fields[len(fields)-1]
+	if strings.Trim(last, "+0123456789*") != "" {
+		return counterparty, ""
+	}
+	name := strings.Join(fields[:len(fields)-1], " ")
+	msisdn, err := NormalizeMSISDN(last)
+	if err != nil || strings.Contains(last, "*") {
+		return name, ""
+	}
+	return name, *msisdn
+}
+
+func parseMPesaSMSTime(date, clock string) (time.Time, error) {
+	for _, layout := range []string{"2/1/06 3:04 PM", "2/1/2006 3:04 PM"} {
+		t, err := time.ParseInLocation(layout, date+" "+clock, nairobiLocation)
+		if err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid M-Pesa transaction time: %s %s", date, clock)
+}
+
+// parseMPesaCallbackTime reads the yyyyMMddHHmmss timestamps used by the
+// Daraja API
+func parseMPesaCallbackTime(value string) (time.Time, error) {
+	t, err := time.ParseInLocation("20060102150405", value, nairobiLocation)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid M-Pesa transaction time: %s", value)
+	}
+	return t, nil
+}
+
+// mpesaSTKCallback is the payload of a Daraja STK push (Lipa na M-Pesa
+// Online) result callback
+type mpesaSTKCallback struct {
+	Body struct {
+		STKCallback struct {
+			ResultCode       json.Number `json:"ResultCode"`
+			ResultDesc       string      `json:"ResultDesc"`
+			CallbackMetadata struct {
+				Item []struct {
+					Name  string      `json:"Name"`
+					Value interface{} `json:"Value"`
+				} `json:"Item"`
+			} `json:"CallbackMetadata"`
+		} `json:"stkCallback"`
+	} `json:"Body"`
+}
+
+// ParseMPesaSTKCallback reads the result of an STK push (Lipa na M-Pesa
+// Online) payment from the callback that Daraja posts. Failed payments
+// (a non zero ResultCode) are returned as errors.
+func ParseMPesaSTKCallback(payload []byte) (*MPesaTransaction, error) {
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	// amounts are read as decimal strings to keep floats out of the sums
+	decoder.UseNumber()
+	callback := mpesaSTKCallback{}
+	if err := decoder.Decode(&callback); err != nil {
+		return nil, fmt.Errorf("unable to decode the STK callback: %v", err)
+	}
+	result := callback.Body.STKCallback
+	if result.ResultCode.String() != "0" {
+		return nil, fmt.Errorf(
+			"the M-Pesa payment failed (%s): %s", result.ResultCode, result.ResultDesc)
+	}
+
+	items := map[string]string{}
+	for _, item := range result.CallbackMetadata.Item {
+		if item.Value != nil {
+			items[item.Name] = fmt.Sprint(item.Value)
+		}
+	}
+
+	transaction := &MPesaTransaction{
+		ReceiptCode: items["MpesaReceiptNumber"],
+		Type:        MPesaTransactionTypeReceived,
+	}
+	if !IsMPesaReceiptCodeValid(transaction.ReceiptCode) {
+		return nil, fmt.Errorf("invalid M-Pesa receipt code: %q", transaction.ReceiptCode)
+	}
+	amount, err := ParseMoney(items["Amount"], CurrencyKES)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the transaction amount: %v", err)
+	}
+	transaction.Amount = amount
+
+	msisdn, err := NormalizeMSISDN(items["PhoneNumber"])
+	if err != nil {
+		return nil, fmt.Errorf("unable to normalize the payer's phone number: %v", err)
+	}
+	transaction.CounterpartyMSISDN = *msisdn
+
+	transaction.TransactionTime, err = parseMPesaCallbackTime(items["TransactionDate"])
+	if err != nil {
+		return nil, err
+	}
+	if balance, ok := items["Balance"]; ok && balance != "" {
+		money, err := ParseMoney(balance, CurrencyKES)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse the balance: %v", err)
+		}
+		transaction.Balance = &money
+	}
+	return transaction, nil
+}
+
+// mpesaC2BConfirmation is the payload of a Daraja C2B (paybill and till)
+// confirmation callback
+type mpesaC2BConfirmation struct {
+	TransID           string `json:"TransID"`
+	TransTime         string `json:"TransTime"`
+	TransAmount       string `json:"TransAmount"`
+	BillRefNumber     string `json:"BillRefNumber"`
+	OrgAccountBalance string `json:"OrgAccountBalance"`
+	MSISDN            string `json:"MSISDN"`
+	FirstName         string `json:"FirstName"`
+	MiddleName        string `json:"MiddleName"`
+	LastName          string `json:"LastName"`
+}
+
+// ParseMPesaC2BConfirmation reads a payment to a paybill or till from the
+// C2B confirmation callback that Daraja posts.
+//
+// Daraja may mask or hash the payer's phone number, in which case
+// CounterpartyMSISDN is left empty.
+func ParseMPesaC2BConfirmation(payload []byte) (*MPesaTransaction, error) {
+	confirmation := mpesaC2BConfirmation{}
+	if err := json.Unmarshal(payload, &confirmation); err != nil {
+		return nil, fmt.Errorf("unable to decode the C2B confirmation: %v", err)
+	}
+	if !IsMPesaReceiptCodeValid(confirmation.TransID) {
+		return nil, fmt.Errorf("invalid M-Pesa receipt code: %q", confirmation.TransID)
+	}
+
+	transaction := &MPesaTransaction{
+		ReceiptCode:   confirmation.TransID,
+		Type:          MPesaTransactionTypeReceived,
+		AccountNumber: confirmation.BillRefNumber,
+		CounterpartyName: strings.Join(strings.Fields(strings.Join([]string{
+			confirmation.FirstName, confirmation.MiddleName, confirmation.LastName,
+		}, " ")), " "),
+	}
+	amount, err := ParseMoney(confirmation.TransAmount, CurrencyKES)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse the transaction amount: %v", err)
+	}
+	transaction.Amount = amount
+
+	if msisdn, err := NormalizeMSISDN(confirmation.MSISDN); err == nil {
+		transaction.CounterpartyMSISDN = *msisdn
+	}
+
+	transaction.TransactionTime, err = parseMPesaCallbackTime(confirmation.TransTime)
+	if err != nil {
+		return nil, err
+	}
+	if confirmation.OrgAccountBalance != "" {
+		balance, err := ParseMoney(confirmation.OrgAccountBalance, CurrencyKES)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse the balance: %v", err)
+		}
+		transaction.Balance = &balance
+	}
+	return transaction, nil
+}
@@ -0,0 +1,263 @@
+package converterandformatter_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/savannahghi/converterandformatter"
+	"github.com/stretchr/testify/assert"
+)
+
+func kes(amount int64) converterandformatter.Money {
+	return converterandformatter.Money{Amount: amount, Currency: converterandformatter.CurrencyKES}
+}
+
+func kesPtr(amount int64) *converterandformatter.Money {
+	m := kes(amount)
+	return &m
+}
+
+func TestIsMPesaReceiptCodeValid(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		want bool
+	}{
+		{name: "valid", code: "QGH7XK2L9P", want: true},
+		{name: "lower case", code: "qgh7xk2l9p", want: false},
+		{name: "too short", code: "QGH7XK2L9", want: false},
+		{name: "too long", code: "QGH7XK2L9PQ", want: false},
+		{name: "starts with a digit", code: "1GH7XK2L9P", want: false},
+		{name: "no digits", code: "SUCCESSFUL", want: false},
+		{name: "punctuation", code: "QGH7XK-L9P", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, converterandformatter.IsMPesaReceiptCodeValid(tt.code))
+		})
+	}
+}
+
+func TestParseMPesaSMS(t *testing.T) {
+	nairobi, err := time.LoadLocation("Africa/Nairobi")
+	assert.Nil(t, err)
+	when := time.Date(2021, 3, 12, 16, 15, 0, 0, nairobi)
+
+	tests := []struct {
+		name    string
+		message string
+		want    *converterandformatter.MPesaTransaction
+		wantErr bool
+	}{
+		{
+			name:    "received",
+			message: "QGH7XK2L9P Confirmed.You have received Ksh1,500.00 from JOHN KAMAU 0722123456 on 12/3/21 at 4:15 PM  New M-PESA balance is Ksh3,200.50. Buy goods with M-PESA.",
+			want: &converterandformatter.MPesaTransaction{
+				ReceiptCode:        "QGH7XK2L9P",
+				Type:               converterandformatter.MPesaTransactionTypeReceived,
+				Amount:             kes(150000),
+				CounterpartyName:   "JOHN KAMAU",
+				CounterpartyMSISDN: "+254722123456",
+				TransactionTime:    when,
+				Balance:            kesPtr(320050),
+			},
+		},
+		{
+			name:    "received from a masked number",
+			message: "QGH7XK2L9P Confirmed.You have received Ksh50.00 from JOHN KAMAU 0722***456 on 12/3/21 at 4:15 PM New M-PESA balance is Ksh50.00.",
+			want: &converterandformatter.MPesaTransaction{
+				ReceiptCode:      "QGH7XK2L9P",
+				Type:             converterandformatter.MPesaTransactionTypeReceived,
+				Amount:           kes(5000),
+				CounterpartyName: "JOHN KAMAU",
+				TransactionTime:  when,
+				Balance:          kesPtr(5000),
+			},
+		},
+		{
+			name:    "received from a business",
+			message: "RKT3AB12CD Confirmed.You have received Ksh10,000.00 from EQUITY BULK ACCOUNT on 12/3/2021 at 4:15 PM New M-PESA balance is Ksh10,000.00.",
+			want: &converterandformatter.MPesaTransaction{
+				ReceiptCode:      "RKT3AB12CD",
+				Type:             converterandformatter.MPesaTransactionTypeReceived,
+				Amount:           kes(1000000),
+				CounterpartyName: "EQUITY BULK ACCOUNT",
+				TransactionTime:  when,
+				Balance:          kesPtr(1000000),
+			},
+		},
+		{
+			name:    "sent",
+			message: "QGH7XK2L9P Confirmed. Ksh500.00 sent to JANE WANJIKU 0711222333 on 12/3/21 at 4:15 PM. New M-PESA balance is Ksh2,700.50. Transaction cost, Ksh7.00.",
+			want: &converterandformatter.MPesaTransaction{
+				ReceiptCode:        "QGH7XK2L9P",
+				Type:               converterandformatter.MPesaTransactionTypeSent,
+				Amount:             kes(50000),
+				CounterpartyName:   "JANE WANJIKU",
+				CounterpartyMSISDN: "+254711222333",
+				TransactionTime:    when,
+				Balance:            kesPtr(270050),
+				TransactionCost:    kesPtr(700),
+			},
+		},
+		{
+			name:    "paybill",
+			message: "QGH7XK2L9P Confirmed. Ksh1,000.00 sent to KPLC PREPAID for account 12345678 on 12/3/21 at 4:15 PM New M-PESA balance is Ksh1,700.50. Transaction cost, Ksh0.00.",
+			want: &converterandformatter.MPesaTransaction{
+				ReceiptCode:      "QGH7XK2L9P",
+				Type:             converterandformatter.MPesaTransactionTypePaid,
+				Amount:           kes(100000),
+				CounterpartyName: "KPLC PREPAID",
+				AccountNumber:    "12345678",
+				TransactionTime:  when,
+				Balance:          kesPtr(170050),
+				TransactionCost:  kesPtr(0),
+			},
+		},
+		{
+			name:    "buy goods",
+			message: "QGH7XK2L9P Confirmed. Ksh250.00 paid to NAIVAS SUPERMARKET. on 12/3/21 at 4:15 PM.New M-PESA balance is Ksh1,450.50. Transaction cost, Ksh0.00.",
+			want: &converterandformatter.MPesaTransaction{
+				ReceiptCode:      "QGH7XK2L9P",
+				Type:             converterandformatter.MPesaTransactionTypePaid,
+				Amount:           kes(25000),
+				CounterpartyName: "NAIVAS SUPERMARKET",
+				TransactionTime:  when,
+				Balance:          kesPtr(145050),
+				TransactionCost:  kesPtr(0),
+			},
+		},
+		{
+			name:    "withdrawal",
+			message: "QGH7XK2L9P Confirmed.on 12/3/21 at 4:15 PMWithdraw Ksh2,000.00 from 123456 - MAMA MBOGA SHOP New M-PESA balance is Ksh1,200.00. Transaction cost, Ksh29.00.",
+			want: &converterandformatter.MPesaTransaction{
+				ReceiptCode:      "QGH7XK2L9P",
+				Type:             converterandformatter.MPesaTransactionTypeWithdrawn,
+				Amount:           kes(200000),
+				CounterpartyName: "MAMA MBOGA SHOP",
+				AccountNumber:    "123456",
+				TransactionTime:  when,
+				Balance:          kesPtr(120000),
+				TransactionCost:  kesPtr(2900),
+			},
+		},
+		{
+			name:    "not an M-Pesa message",
+			message: "Your appointment is confirmed for tomorrow",
+			wantErr: true,
+		},
+		{
+			name:    "unknown template",
+			message: "QGH7XK2L9P Confirmed. You bought Ksh50.00 of airtime on 12/3/21 at 4:15 PM.",
+			wantErr: true,
+		},
+		{
+			name:    "no transaction time",
+			message: "QGH7XK2L9P Confirmed. Ksh250.00 paid to NAIVAS SUPERMARKET. on 12/3/21.",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := converterandformatter.ParseMPesaSMS(tt.message)
+			if tt.wantErr {
+				assert.NotNil(t, err)
+				assert.Nil(t, got)
+				return
+			}
+			assert.Nil(t, err)
+			assert.True(t, tt.want.TransactionTime.Equal(got.TransactionTime))
+			got.TransactionTime = tt.want.TransactionTime
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseMPesaSTKCallback(t *testing.T) {
+	success := `{
+		"Body": {
+			"stkCallback": {
+				"MerchantRequestID": "29115-34620561-1",
+				"CheckoutRequestID": "ws_CO_191220191020363925",
+				"ResultCode": 0,
+				"ResultDesc": "The service request is processed successfully.",
+				"CallbackMetadata": {
+					"Item": [
+						{"Name": "Amount", "Value": 1.10},
+						{"Name": "MpesaReceiptNumber", "Value": "NLJ7RT61SV"},
+						{"Name": "Balance"},
+						{"Name": "TransactionDate", "Value": 20191219102115},
+						{"Name": "PhoneNumber", "Value": 254708374149}
+					]
+				}
+			}
+		}
+	}`
+	got, err := converterandformatter.ParseMPesaSTKCallback([]byte(success))
+	assert.Nil(t, err)
+	assert.Equal(t, "NLJ7RT61SV", got.ReceiptCode)
+	assert.Equal(t, converterandformatter.MPesaTransactionTypeReceived, got.Type)
+	assert.Equal(t, kes(110), got.Amount)
+	assert.Equal(t, "+254708374149", got.CounterpartyMSISDN)
+	assert.Nil(t, got.Balance)
+	assert.Equal(t, "2019-12-19T10:21:15+03:00", got.TransactionTime.Format(time.RFC3339))
+
+	cancelled := `{
+		"Body": {
+			"stkCallback": {
+				"MerchantRequestID": "29115-34620561-1",
+				"CheckoutRequestID": "ws_CO_191220191020363925",
+				"ResultCode": 1032,
+				"ResultDesc": "Request cancelled by user."
+			}
+		}
+	}`
+	_, err = converterandformatter.ParseMPesaSTKCallback([]byte(cancelled))
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "cancelled")
+
+	_, err = converterandformatter.ParseMPesaSTKCallback([]byte(`{"Body": `))
+	assert.NotNil(t, err)
+}
+
+func TestParseMPesaC2BConfirmation(t *testing.T) {
+	confirmation := `{
+		"TransactionType": "Pay Bill",
+		"TransID": "RKTQDM7W6S",
+		"TransTime": "20191122063845",
+		"TransAmount": "10.00",
+		"BusinessShortCode": "600638",
+		"BillRefNumber": "A123",
+		"InvoiceNumber": "",
+		"OrgAccountBalance": "49197.00",
+		"ThirdPartyTransID": "",
+		"MSISDN": "254708374149",
+		"FirstName": "John",
+		"MiddleName": "",
+		"LastName": "Doe"
+	}`
+	got, err := converterandformatter.ParseMPesaC2BConfirmation([]byte(confirmation))
+	assert.Nil(t, err)
+	assert.Equal(t, "RKTQDM7W6S", got.ReceiptCode)
+	assert.Equal(t, kes(1000), got.Amount)
+	assert.Equal(t, "A123", got.AccountNumber)
+	assert.Equal(t, "John Doe", got.CounterpartyName)
+	assert.Equal(t, "+254708374149", got.CounterpartyMSISDN)
+	assert.Equal(t, kesPtr(4919700), got.Balance)
+	assert.Equal(t, "2019-11-22T06:38:45+03:00", got.TransactionTime.Format(time.RFC3339))
+
+	hashed := `{
+		"TransID": "RKTQDM7W6S",
+		"TransTime": "20191122063845",
+		"TransAmount": "10",
+		"MSISDN": "2f3c6b1d9e0a4c5b8d7e6f5a4b3c2d1e0f9a8b7c6d5e4f3a2b1c0d9e8f7a6b5c"
+	}`
+	got, err = converterandformatter.ParseMPesaC2BConfirmation([]byte(hashed))
+	assert.Nil(t, err)
+	assert.Empty(t, got.CounterpartyMSISDN)
+	assert.Nil(t, got.Balance)
+
+	invalid := `{"TransID": "not-a-code", "TransTime": "20191122063845", "TransAmount": "10"}`
+	_, err = converterandformatter.ParseMPesaC2BConfirmation([]byte(invalid))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,92 @@
+package converterandformatter
+
+import (
+	"container/list"
+	"sync"
+)
+
+// MSISDNNormalizer normalizes phone numbers like NormalizeMSISDN and keeps
+// the results for the most recently seen numbers in a fixed size LRU cache.
+//
+// It is meant for bulk imports where the same numbers recur. It is safe for
+// concurrent use.
+type MSISDNNormalizer struct {
+	mu       sync.Mutex
+	capacity int
+	entries  map[string]*list.Element
+	order    *list.List
+}
+
+type msisdnCacheEntry struct {
+	msisdn     string
+	normalized string
+	err        error
+}
+
+// NewMSISDNNormalizer initializes a normalizer that caches the results for
+// up to capacity phone numbers. A capacity below one disables caching.
+func NewMSISDNNormalizer(capacity int) *MSISDNNormalizer {
+	return &MSISDNNormalizer{
+		capacity: capacity,
+		entries:  map[string]*list.Element{},
+		order:    list.New(),
+	}
+}
+
+// Normalize returns the international format of a phone number e.g
+// +2547........ or the error NormalizeMSISDN returns for it
+func (n *MSISDNNormalizer) Normalize(msisdn string) (string, error) {
+	n.mu.Lock()
+	if el, ok := n.entries[msisdn]; ok {
+		n.order.MoveToFront(el)
+		entry := el.Value.(*msisdnCacheEntry)
+		n.mu.Unlock()
+		return entry.normalized, entry.err
+	}
+	n.mu.Unlock()
+
+	entry := &msisdnCacheEntry{msisdn: msisdn}
+	normalized, err := NormalizeMSISDN(msisdn)
+	if err != nil {
+		entry.err = err
+	} else {
+		entry.normalized = *normalized
+	}
+	n.store(entry)
+	return entry.normalized, entry.err
+}
+
+// Contains reports whether the result for a phone number is in the cache.
+// It does not count as a use of the number.
+func (n *MSISDNNormalizer) Contains(msisdn string) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	_, ok := n.entries[msisdn]
+	return ok
+}
+
+// Len returns the number of phone numbers in the cache
+func (n *MSISDNNormalizer) Len() int {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return n.order.Len()
+}
+
+func (n *MSISDNNormalizer) store(entry *msisdnCacheEntry) {
+	if n.capacity < 1 {
+		return
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if el, ok := n.entries[entry.msisdn]; ok {
+		// another goroutine normalized the same number in the meantime
+		n.order.MoveToFront(el)
+		return
+	}
+	n.entries[entry.msisdn] = n.order.PushFront(entry)
+	for n.order.Len() > n.capacity {
+		oldest := n.order.Back()
+		n.order.Remove(oldest)
+		delete(n.entries, oldest.Value.(*msisdnCacheEntry).msisdn)
+	}
+}
@@ -0,0 +1,67 @@
+package converterandformatter_test
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/savannahghi/converterandformatter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMSISDNNormalizer(t *testing.T) {
+	n := converterandformatter.NewMSISDNNormalizer(2)
+
+	got, err := n.Normalize("0722000000")
+	assert.Nil(t, err)
+	assert.Equal(t, "+254722000000", got)
+
+	_, err = n.Normalize("not a phone")
+	assert.NotNil(t, err)
+	assert.Equal(t, 2, n.Len())
+
+	// cached results are returned as they were first computed
+	got, err = n.Normalize("0722000000")
+	assert.Nil(t, err)
+	assert.Equal(t, "+254722000000", got)
+	_, err = n.Normalize("not a phone")
+	assert.NotNil(t, err)
+
+	// the least recently used number is evicted
+	_, err = n.Normalize("0711000000")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, n.Len())
+	assert.False(t, n.Contains("0722000000"))
+	assert.True(t, n.Contains("not a phone"))
+	assert.True(t, n.Contains("0711000000"))
+}
+
+func TestMSISDNNormalizer_NoCache(t *testing.T) {
+	n := converterandformatter.NewMSISDNNormalizer(0)
+	got, err := n.Normalize("0722000000")
+	assert.Nil(t, err)
+	assert.Equal(t, "+254722000000", got)
+	assert.Equal(t, 0, n.Len())
+}
+
+func TestMSISDNNormalizer_Concurrent(t *testing.T) {
+	n := converterandformatter.NewMSISDNNormalizer(3)
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			got, err := n.Normalize(benchmarkMSISDNs[i%2])
+			assert.Nil(t, err)
+			assert.NotEmpty(t, got)
+		}(i)
+	}
+	wg.Wait()
+	assert.Equal(t, 2, n.Len())
+}
+
+func BenchmarkMSISDNNormalizer(b *testing.B) {
+	n := converterandformatter.NewMSISDNNormalizer(len(benchmarkMSISDNs))
+	for i := 0; i < b.N; i++ {
+		_, _ = n.Normalize(benchmarkMSISDNs[i%len(benchmarkMSISDNs)])
+	}
+}
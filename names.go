@@ -0,0 +1,230 @@
+package converterandformatter
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// nameParticles are written in lower case and belong to the name that
+// follows them e.g Ngugi wa Thiong'o and Daniel arap Moi
+var nameParticles = []string{"wa", "arap", "bin", "binti", "bint"}
+
+// nameApostrophePrefixes are capitalized along with the rest of the name
+// e.g O'Brien and D'Souza
+var nameApostrophePrefixes = []string{"o", "d"}
+
+// nameTitles are the honorifics recognized before a name, in their
+// normalized form
+var nameTitles = []string{"Dr", "Mr", "Mrs", "Ms", "Miss", "Prof", "Eng", "Hon", "Rev"}
+
+// nameSuffixes are the generational suffixes recognized after a comma
+var nameSuffixes = []string{"Jr", "Sr", "II", "III", "IV"}
+
+// PersonName is a person's name split into its parts
+type PersonName struct {
+	Title       string   `json:"title,omitempty" firestore:"title,omitempty"`
+	GivenName   string   `json:"givenName" firestore:"givenName"`
+	MiddleNames []string `json:"middleNames,omitempty" firestore:"middleNames,omitempty"`
+	FamilyName  string   `json:"familyName,omitempty" firestore:"familyName,omitempty"`
+	Suffix      string   `json:"suffix,omitempty" firestore:"suffix,omitempty"`
+}
+
+// NormalizePersonName collapses the whitespace in a name and capitalizes each
+// part of it e.g "JOHN  kamau  doe" becomes "John Kamau Doe".
+//
+// Hyphenated parts are capitalized separately (Wanjiru-Kamau), Mc and O'
+// prefixes keep the capital that follows them (McDonald, O'Brien) and the
+// particles wa and arap stay in lower case before the name they belong to
+// (Ngugi wa Thiong'o). Names that start with Mch, such as Mcharo and
+// Mchangamwe, are not treated as Mc names.
+func NormalizePersonName(name string) string {
+	words := strings.Fields(name)
+	for i, word := range words {
+		if i < len(words)-1 && Contains(nameParticles, strings.ToLower(word)) {
+			words[i] = strings.ToLower(word)
+			continue
+		}
+		parts := strings.Split(word, "-")
+		for j, part := range parts {
+			parts[j] = capitalizeNamePart(part)
+		}
+		words[i] = strings.Join(parts, "-")
+	}
+	return strings.Join(words, " ")
+}
+
+// capitalizeNamePart upper cases the first letter of a name and lower cases
+// the rest, keeping the capital after Mc (but not Mch) and O' prefixes
+func capitalizeNamePart(part string) string {
+	lower := strings.ToLower(part)
+	runes := []rune(lower)
+	if len(runes) == 0 {
+		return lower
+	}
+	runes[0] = unicode.ToUpper(runes[0])
+
+	switch {
+	case len(runes) > 2 && runes[0] == 'M' && runes[1] == 'c' && runes[2] != 'h':
+		runes[2] = unicode.ToUpper(runes[2])
+	case len(runes) > 2 && (runes[1] == '\'' || runes[1] == '’') &&
+		Contains(nameApostrophePrefixes, string(unicode.ToLower(runes[0]))):
+		runes[2] = unicode.ToUpper(runes[2])
+	}
+	return string(runes)
+}
+
+// ParsePersonName normalizes a name and splits it into a title, given name,
+// middle names, family name and suffix.
+//
+// Names are read given name first, with the last name as the family name
+// e.g "John Kamau Doe", unless they are written family name first with a
+// comma e.g "Doe, John Kamau". A name that is left on its own after a title
+// is the family name e.g "Dr Doe". A suffix such as Jr is recognized after a
+// comma e.g "Doe, John, Jr" and "John Doe, Jr". A particle joins the name
+// after it, so the family name of "Daniel Toroitich arap Moi" is "arap Moi".
+func ParsePersonName(name string) PersonName {
+	parsed := PersonName{}
+	parts := Filter(strings.Split(name, ","), func(part string) bool {
+		return strings.TrimSpace(part) != ""
+	})
+	if len(parts) > 1 {
+		suffix := nameSuffix(parts[len(parts)-1])
+		if suffix != "" {
+			parsed.Suffix = suffix
+			parts = parts[:len(parts)-1]
+		}
+	}
+	family := ""
+	if len(parts) > 1 {
+		family = NormalizePersonName(parts[0])
+		parts = parts[1:]
+	}
+
+	words := groupNameParticles(strings.Fields(NormalizePersonName(strings.Join(parts, " "))))
+	if len(words) > 1 {
+		title := strings.TrimSuffix(words[0], ".")
+		if Contains(nameTitles, title) {
+			parsed.Title = title
+			words = words[1:]
+			if family == "" && len(words) == 1 {
+				family = words[0]
+				words = nil
+			}
+		}
+	}
+
+	if family == "" && len(words) > 1 {
+		family = words[len(words)-1]
+		words = words[:len(words)-1]
+	}
+	parsed.FamilyName = family
+	if len(words) > 0 {
+		parsed.GivenName = words[0]
+		if len(words) > 1 {
+			parsed.MiddleNames = words[1:]
+		}
+	}
+	return parsed
+}
+
+// nameSuffix returns the normalized form of a suffix such as "jr." or an
+// empty string if the text is not a suffix
+func nameSuffix(text string) string {
+	text = strings.TrimSuffix(strings.TrimSpace(text), ".")
+	for _, suffix := range nameSuffixes {
+		if strings.EqualFold(text, suffix) {
+			return suffix
+		}
+	}
+	return ""
+}
+
+// groupNameParticles joins each particle to the name that follows it
+func groupNameParticles(words []string) []string {
+	grouped := []string{}
+	for i := 0; i < len(words); i++ {
+		if i < len(words)-1 && Contains(nameParticles, words[i]) {
+			grouped = append(grouped, words[i]+" "+words[i+1])
+			i++
+			continue
+		}
+		grouped = append(grouped, words[i])
+	}
+	return grouped
+}
+
+// FullName returns the name's parts in order e.g "John Kamau Doe Jr"
+func (n PersonName) FullName() string {
+	return joinNameParts(append(append([]string{n.GivenName}, n.MiddleNames...), n.FamilyName, n.Suffix)...)
+}
+
+// ShortName returns the given and family names e.g "John Doe"
+func (n PersonName) ShortName() string {
+	return joinNameParts(n.GivenName, n.FamilyName)
+}
+
+// DisplayName returns the title, if there is one, with the given and family
+// names e.g "Dr Jane Wanjiku"
+func (n PersonName) DisplayName() string {
+	return joinNameParts(n.Title, n.GivenName, n.FamilyName)
+}
+
+// AbbreviatedName returns the given and middle names as initials followed by
+// the family name e.g "J. K. Doe"
+func (n PersonName) AbbreviatedName() string {
+	parts := []string{}
+	for _, name := range append([]string{n.GivenName}, n.MiddleNames...) {
+		if initial := nameInitial(name); initial != "" {
+			parts = append(parts, initial+".")
+		}
+	}
+	return joinNameParts(append(parts, n.FamilyName)...)
+}
+
+// SortName returns the name family name first, for sorting e.g
+// "Doe, John Kamau". A particle is moved after the given names so that the
+// name sorts on the family name that follows it e.g "Moi, Daniel arap".
+func (n PersonName) SortName() string {
+	family, particle := n.FamilyName, ""
+	if words := strings.Fields(family); len(words) > 1 && Contains(nameParticles, words[0]) {
+		family, particle = strings.Join(words[1:], " "), words[0]
+	}
+	rest := joinNameParts(append(append([]string{n.GivenName}, n.MiddleNames...), particle)...)
+	if family == "" || rest == "" {
+		return joinNameParts(family, rest)
+	}
+	return family + ", " + rest
+}
+
+// Initials returns the first letter of each part of the name, skipping
+// particles, e.g "JKD" for John Kamau Doe and "NT" for Ngugi wa Thiong'o
+func (n PersonName) Initials() string {
+	var b strings.Builder
+	for _, name := range append(append([]string{n.GivenName}, n.MiddleNames...), n.FamilyName) {
+		b.WriteString(nameInitial(name))
+	}
+	return b.String()
+}
+
+// nameInitial returns the upper cased first letter of a name, skipping any
+// particle that the name starts with
+func nameInitial(name string) string {
+	words := strings.Fields(name)
+	if len(words) == 0 {
+		return ""
+	}
+	word := words[0]
+	if len(words) > 1 && Contains(nameParticles, word) {
+		word = words[1]
+	}
+	r, _ := utf8.DecodeRuneInString(word)
+	return string(unicode.ToUpper(r))
+}
+
+// joinNameParts joins the non empty parts of a name with spaces
+func joinNameParts(parts ...string) string {
+	return strings.Join(Filter(parts, func(part string) bool {
+		return part != ""
+	}), " ")
+}
@@ -0,0 +1,230 @@
+package converterandformatter_test
+
+import (
+	"testing"
+
+	"github.com/savannahghi/converterandformatter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizePersonName(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "mixed case and spacing", input: "JOHN  kamau  doe", want: "John Kamau Doe"},
+		{name: "surrounding whitespace", input: "\tAkinyi\n", want: "Akinyi"},
+		{name: "hyphenated", input: "mary wanjiru-KAMAU", want: "Mary Wanjiru-Kamau"},
+		{name: "wa", input: "NGUGI WA THIONG'O", want: "Ngugi wa Thiong'o"},
+		{name: "arap", input: "daniel toroitich Arap moi", want: "Daniel Toroitich arap Moi"},
+		{name: "bin", input: "hassan BIN ali", want: "Hassan bin Ali"},
+		{name: "Mc", input: "peter mcdonald", want: "Peter McDonald"},
+		{name: "Mac is not a prefix", input: "MACHARIA", want: "Macharia"},
+		{name: "Mch is not Mc", input: "MCHARO MWAKIO", want: "Mcharo Mwakio"},
+		{name: "lower case Mch", input: "mchangamwe ali", want: "Mchangamwe Ali"},
+		{name: "O'", input: "sean o'brien", want: "Sean O'Brien"},
+		{name: "D'", input: "ANITA D’SOUZA", want: "Anita D’Souza"},
+		{name: "particle as the last word", input: "juma wa", want: "Juma Wa"},
+		{name: "non ASCII letters", input: "émile ÇELIK", want: "Émile Çelik"},
+		{name: "empty", input: "   ", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, converterandformatter.NormalizePersonName(tt.input))
+		})
+	}
+}
+
+func TestParsePersonName(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  converterandformatter.PersonName
+	}{
+		{
+			name:  "given, middle and family",
+			input: "JOHN  kamau  doe",
+			want: converterandformatter.PersonName{
+				GivenName:   "John",
+				MiddleNames: []string{"Kamau"},
+				FamilyName:  "Doe",
+			},
+		},
+		{
+			name:  "given and family",
+			input: "akinyi otieno",
+			want:  converterandformatter.PersonName{GivenName: "Akinyi", FamilyName: "Otieno"},
+		},
+		{
+			name:  "single name",
+			input: "Wanjiku",
+			want:  converterandformatter.PersonName{GivenName: "Wanjiku"},
+		},
+		{
+			name:  "wa",
+			input: "ngugi wa thiong'o",
+			want:  converterandformatter.PersonName{GivenName: "Ngugi", FamilyName: "wa Thiong'o"},
+		},
+		{
+			name:  "arap",
+			input: "Daniel Toroitich arap Moi",
+			want: converterandformatter.PersonName{
+				GivenName:   "Daniel",
+				MiddleNames: []string{"Toroitich"},
+				FamilyName:  "arap Moi",
+			},
+		},
+		{
+			name:  "several middle names",
+			input: "Mary Atieno Wanjiru Kamau",
+			want: converterandformatter.PersonName{
+				GivenName:   "Mary",
+				MiddleNames: []string{"Atieno", "Wanjiru"},
+				FamilyName:  "Kamau",
+			},
+		},
+		{
+			name:  "family name first",
+			input: "KAMAU, john mwangi",
+			want: converterandformatter.PersonName{
+				GivenName:   "John",
+				MiddleNames: []string{"Mwangi"},
+				FamilyName:  "Kamau",
+			},
+		},
+		{
+			name:  "family name first with a particle",
+			input: "wa thiong'o, ngugi",
+			want:  converterandformatter.PersonName{GivenName: "Ngugi", FamilyName: "wa Thiong'o"},
+		},
+		{
+			name:  "title",
+			input: "dr. jane wanjiku",
+			want: converterandformatter.PersonName{
+				Title:      "Dr",
+				GivenName:  "Jane",
+				FamilyName: "Wanjiku",
+			},
+		},
+		{
+			name:  "title and family name",
+			input: "Dr Doe",
+			want:  converterandformatter.PersonName{Title: "Dr", FamilyName: "Doe"},
+		},
+		{
+			name:  "family name first with a suffix",
+			input: "Doe, John, Jr",
+			want: converterandformatter.PersonName{
+				GivenName:  "John",
+				FamilyName: "Doe",
+				Suffix:     "Jr",
+			},
+		},
+		{
+			name:  "suffix after a comma",
+			input: "john kamau doe, III",
+			want: converterandformatter.PersonName{
+				GivenName:   "John",
+				MiddleNames: []string{"Kamau"},
+				FamilyName:  "Doe",
+				Suffix:      "III",
+			},
+		},
+		{
+			name:  "repeated commas",
+			input: "Doe,, John,",
+			want:  converterandformatter.PersonName{GivenName: "John", FamilyName: "Doe"},
+		},
+		{
+			name:  "title-like single name",
+			input: "Hon",
+			want:  converterandformatter.PersonName{GivenName: "Hon"},
+		},
+		{
+			name:  "empty",
+			input: "",
+			want:  converterandformatter.PersonName{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, converterandformatter.ParsePersonName(tt.input))
+		})
+	}
+}
+
+func TestPersonName_Formats(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		full        string
+		short       string
+		display     string
+		abbreviated string
+		sort        string
+		initials    string
+	}{
+		{
+			name:        "given, middle and family",
+			input:       "JOHN  kamau  doe",
+			full:        "John Kamau Doe",
+			short:       "John Doe",
+			display:     "John Doe",
+			abbreviated: "J. K. Doe",
+			sort:        "Doe, John Kamau",
+			initials:    "JKD",
+		},
+		{
+			name:        "particle",
+			input:       "Daniel Toroitich arap Moi",
+			full:        "Daniel Toroitich arap Moi",
+			short:       "Daniel arap Moi",
+			display:     "Daniel arap Moi",
+			abbreviated: "D. T. arap Moi",
+			sort:        "Moi, Daniel Toroitich arap",
+			initials:    "DTM",
+		},
+		{
+			name:        "title",
+			input:       "Prof. Wangari Muta Maathai",
+			full:        "Wangari Muta Maathai",
+			short:       "Wangari Maathai",
+			display:     "Prof Wangari Maathai",
+			abbreviated: "W. M. Maathai",
+			sort:        "Maathai, Wangari Muta",
+			initials:    "WMM",
+		},
+		{
+			name:        "suffix",
+			input:       "Doe, John Kamau, Jr.",
+			full:        "John Kamau Doe Jr",
+			short:       "John Doe",
+			display:     "John Doe",
+			abbreviated: "J. K. Doe",
+			sort:        "Doe, John Kamau",
+			initials:    "JKD",
+		},
+		{
+			name:        "single name",
+			input:       "akinyi",
+			full:        "Akinyi",
+			short:       "Akinyi",
+			display:     "Akinyi",
+			abbreviated: "A.",
+			sort:        "Akinyi",
+			initials:    "A",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n := converterandformatter.ParsePersonName(tt.input)
+			assert.Equal(t, tt.full, n.FullName())
+			assert.Equal(t, tt.short, n.ShortName())
+			assert.Equal(t, tt.display, n.DisplayName())
+			assert.Equal(t, tt.abbreviated, n.AbbreviatedName())
+			assert.Equal(t, tt.sort, n.SortName())
+			assert.Equal(t, tt.initials, n.Initials())
+		})
+	}
+}
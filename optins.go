@@ -0,0 +1,236 @@
+package converterandformatter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/savannahghi/firebasetools"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// OptInPreferenceID returns the document ID under which the consent of a
+// phone number for a channel and topic is stored.
+//
+// The ID is deterministic so that repeated updates overwrite one record
+// instead of piling up duplicates.
+func OptInPreferenceID(msisdn string, channel OptInChannel, topic OptInTopic) string {
+	return fmt.Sprintf("%s_%s_%s", strings.TrimPrefix(msisdn, "+"), channel, topic)
+}
+
+// SetOptInPreference records whether the supplied phone number consents to
+// receive communication about a topic over a channel
+func SetOptInPreference(
+	msisdn string, channel OptInChannel, topic OptInTopic, optedIn bool,
+	firestoreClient *firestore.Client) (*PhoneOptInPreference, error) {
+	normalized, err := NormalizeMSISDN(msisdn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid phone format: %v", err)
+	}
+	if !channel.IsValid() {
+		return nil, fmt.Errorf("invalid opt in channel: %s", channel)
+	}
+	if !topic.IsValid() {
+		return nil, fmt.Errorf("invalid opt in topic: %s", topic)
+	}
+
+	preference := PhoneOptInPreference{
+		MSISDN:    *normalized,
+		Channel:   channel,
+		Topic:     topic,
+		OptedIn:   optedIn,
+		UpdatedAt: time.Now(),
+	}
+	err = saveOptInPreference(preference, firestoreClient)
+	if err != nil {
+		return nil, err
+	}
+	return &preference, nil
+}
+
+func saveOptInPreference(
+	preference PhoneOptInPreference, firestoreClient *firestore.Client) error {
+	err := firebasetools.UpdateRecordOnFirestore(
+		firestoreClient,
+		CollectionName(PhoneOptInPreferenceCollectionName),
+		OptInPreferenceID(preference.MSISDN, preference.Channel, preference.Topic),
+		preference,
+	)
+	if err != nil {
+		return fmt.Errorf("unable to save opt in preference: %v", err)
+	}
+	return nil
+}
+
+// getOptInPreference returns the recorded preference of a (normalized) phone
+// number for a channel and topic, or nil if none has been recorded
+func getOptInPreference(
+	msisdn string, channel OptInChannel, topic OptInTopic,
+	firestoreClient *firestore.Client) (*PhoneOptInPreference, error) {
+	ctx := context.Background()
+	doc, err := firestoreClient.Collection(
+		CollectionName(PhoneOptInPreferenceCollectionName),
+	).Doc(OptInPreferenceID(msisdn, channel, topic)).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve opt in preference: %v", err)
+	}
+	var preference PhoneOptInPreference
+	err = doc.DataTo(&preference)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read opt in preference: %v", err)
+	}
+	return &preference, nil
+}
+
+// IsAllowed returns true if the supplied phone number has opted in to
+// receive communication about a topic over a channel.
+//
+// Consent is never assumed: a phone number with no recorded preference for
+// the channel and topic is not allowed.
+func IsAllowed(
+	msisdn string, channel OptInChannel, topic OptInTopic,
+	firestoreClient *firestore.Client) (bool, error) {
+	normalized, err := NormalizeMSISDN(msisdn)
+	if err != nil {
+		return false, fmt.Errorf("invalid phone format: %v", err)
+	}
+	if !channel.IsValid() {
+		return false, fmt.Errorf("invalid opt in channel: %s", channel)
+	}
+	if !topic.IsValid() {
+		return false, fmt.Errorf("invalid opt in topic: %s", topic)
+	}
+
+	query := firestoreClient.Collection(
+		CollectionName(PhoneOptInPreferenceCollectionName),
+	).Where(
+		"msisdn", "==", *normalized,
+	).Where(
+		"channel", "==", channel,
+	).Where(
+		"topic", "==", topic,
+	)
+	ctx := context.Background()
+	docs, err := query.Documents(ctx).GetAll()
+	if err != nil {
+		return false, fmt.Errorf("unable to retrieve opt in preferences: %v", err)
+	}
+	if len(docs) == 0 {
+		return false, nil
+	}
+
+	var preference PhoneOptInPreference
+	err = docs[0].DataTo(&preference)
+	if err != nil {
+		return false, fmt.Errorf("unable to read opt in preference: %v", err)
+	}
+	return preference.OptedIn, nil
+}
+
+// optOutKeywords are the SMS replies, in English and Swahili, that withdraw
+// consent to receive SMS messages
+var optOutKeywords = []string{
+	"STOP", "STOPALL", "UNSUBSCRIBE", "CANCEL", "END", "QUIT",
+	"SIMAMA", "ACHA", "ONDOA",
+}
+
+// optInKeywords are the SMS replies, in English and Swahili, that restore
+// consent to receive SMS messages
+var optInKeywords = []string{
+	"START", "SUBSCRIBE", "UNSTOP",
+	"ANZA", "JIUNGE",
+}
+
+// ParseOptInKeyword inspects the body of an inbound SMS for an opt in or opt
+// out keyword e.g STOP, START, SIMAMA or ANZA.
+//
+// The whole message, ignoring case, surrounding whitespace and trailing
+// punctuation, must be the keyword: replies such as "Cancel my appointment"
+// are not opt outs. The second return value is false when the message is
+// not a known keyword.
+func ParseOptInKeyword(body string) (optIn bool, ok bool) {
+	word := strings.ToUpper(strings.TrimRight(strings.TrimSpace(body), ".,!?;:\"' "))
+	if Contains(optOutKeywords, word) {
+		return false, true
+	}
+	if Contains(optInKeywords, word) {
+		return true, true
+	}
+	return false, false
+}
+
+// HandleOptInSMS updates the opt in status of the sender of an inbound SMS
+// that carries an opt in or opt out keyword.
+//
+// The sender's PhoneOptIn records are updated (or one is created). An opt
+// out withdraws every SMS topic the sender had consented to; an opt in only
+// restores the topics that an earlier opt out keyword withdrew, so consent
+// to a topic is never granted by a keyword alone. A nil PhoneOptIn and a nil
+// error are returned when the message is not a keyword message.
+func HandleOptInSMS(
+	msisdn, body string, firestoreClient *firestore.Client) (*PhoneOptIn, error) {
+	optedIn, ok := ParseOptInKeyword(body)
+	if !ok {
+		return nil, nil
+	}
+	normalized, err := NormalizeMSISDN(msisdn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid phone format: %v", err)
+	}
+
+	data := PhoneOptIn{
+		MSISDN:  *normalized,
+		OptedIn: optedIn,
+	}
+	query := firestoreClient.Collection(CollectionName(PhoneOptInCollectionName)).Where(
+		"msisdn", "==", *normalized,
+	)
+	ctx := context.Background()
+	docs, err := query.Documents(ctx).GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve phone opt ins: %v", err)
+	}
+	if len(docs) == 0 {
+		_, err = firebasetools.SaveDataToFirestore(
+			firestoreClient, CollectionName(PhoneOptInCollectionName), data)
+		if err != nil {
+			return nil, fmt.Errorf("unable to save phone opt in: %v", err)
+		}
+	}
+	for _, doc := range docs {
+		err = firebasetools.UpdateRecordOnFirestore(
+			firestoreClient, CollectionName(PhoneOptInCollectionName), doc.Ref.ID, data)
+		if err != nil {
+			return nil, fmt.Errorf("unable to update phone opt in: %v", err)
+		}
+	}
+
+	for _, topic := range AllOptInTopics {
+		preference, err := getOptInPreference(
+			*normalized, OptInChannelSMS, topic, firestoreClient)
+		if err != nil {
+			return nil, err
+		}
+		if preference == nil || preference.OptedIn == optedIn {
+			continue
+		}
+		if optedIn && !preference.OptedOutByKeyword {
+			// withdrawn deliberately, not by the keyword being reversed
+			continue
+		}
+		preference.OptedIn = optedIn
+		preference.OptedOutByKeyword = !optedIn
+		preference.UpdatedAt = time.Now()
+		err = saveOptInPreference(*preference, firestoreClient)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &data, nil
+}
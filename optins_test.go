@@ -0,0 +1,293 @@
+package converterandformatter_test
+
+import (
+	"context"
+	"testing"
+
+	"cloud.google.com/go/firestore"
+	"github.com/savannahghi/converterandformatter"
+	"github.com/savannahghi/firebasetools"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOptInChannelAndTopicIsValid(t *testing.T) {
+	for _, c := range converterandformatter.AllOptInChannels {
+		assert.True(t, c.IsValid())
+		assert.Equal(t, string(c), c.String())
+	}
+	assert.False(t, converterandformatter.OptInChannel("EMAIL").IsValid())
+
+	for _, tp := range converterandformatter.AllOptInTopics {
+		assert.True(t, tp.IsValid())
+		assert.Equal(t, string(tp), tp.String())
+	}
+	assert.False(t, converterandformatter.OptInTopic("POLITICS").IsValid())
+}
+
+func TestOptInPreferenceID(t *testing.T) {
+	got := converterandformatter.OptInPreferenceID(
+		"+254722000000",
+		converterandformatter.OptInChannelSMS,
+		converterandformatter.OptInTopicMarketing,
+	)
+	assert.Equal(t, "254722000000_SMS_MARKETING", got)
+}
+
+func TestSetOptInPreference_InvalidInput(t *testing.T) {
+	type args struct {
+		msisdn  string
+		channel converterandformatter.OptInChannel
+		topic   converterandformatter.OptInTopic
+	}
+	tests := []struct {
+		name string
+		args args
+	}{
+		{
+			name: "invalid phone number",
+			args: args{
+				msisdn:  "not a phone",
+				channel: converterandformatter.OptInChannelSMS,
+				topic:   converterandformatter.OptInTopicMarketing,
+			},
+		},
+		{
+			name: "invalid channel",
+			args: args{
+				msisdn:  "0722000000",
+				channel: "EMAIL",
+				topic:   converterandformatter.OptInTopicMarketing,
+			},
+		},
+		{
+			name: "invalid topic",
+			args: args{
+				msisdn:  "0722000000",
+				channel: converterandformatter.OptInChannelSMS,
+				topic:   "POLITICS",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := converterandformatter.SetOptInPreference(
+				tt.args.msisdn, tt.args.channel, tt.args.topic, true, nil)
+			assert.NotNil(t, err)
+
+			_, err = converterandformatter.IsAllowed(
+				tt.args.msisdn, tt.args.channel, tt.args.topic, nil)
+			assert.NotNil(t, err)
+		})
+	}
+}
+
+func TestSetOptInPreferenceAndIsAllowed(t *testing.T) {
+	fc, err := firebasetools.GetFirestoreClient(context.Background())
+	if err != nil {
+		t.Fatalf("unable to initialize Firestore client: %v", err)
+	}
+
+	msisdn := "0722000000"
+	_, err = converterandformatter.SetOptInPreference(
+		msisdn, converterandformatter.OptInChannelSMS,
+		converterandformatter.OptInTopicAppointments, true, fc)
+	assert.Nil(t, err)
+	_, err = converterandformatter.SetOptInPreference(
+		msisdn, converterandformatter.OptInChannelSMS,
+		converterandformatter.OptInTopicMarketing, false, fc)
+	assert.Nil(t, err)
+
+	type args struct {
+		channel         converterandformatter.OptInChannel
+		topic           converterandformatter.OptInTopic
+		firestoreClient *firestore.Client
+	}
+	tests := []struct {
+		name string
+		args args
+		want bool
+	}{
+		{
+			name: "opted in",
+			args: args{
+				channel:         converterandformatter.OptInChannelSMS,
+				topic:           converterandformatter.OptInTopicAppointments,
+				firestoreClient: fc,
+			},
+			want: true,
+		},
+		{
+			name: "opted out",
+			args: args{
+				channel:         converterandformatter.OptInChannelSMS,
+				topic:           converterandformatter.OptInTopicMarketing,
+				firestoreClient: fc,
+			},
+			want: false,
+		},
+		{
+			name: "no preference recorded",
+			args: args{
+				channel:         converterandformatter.OptInChannelVoice,
+				topic:           converterandformatter.OptInTopicHealthTips,
+				firestoreClient: fc,
+			},
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := converterandformatter.IsAllowed(
+				msisdn, tt.args.channel, tt.args.topic, tt.args.firestoreClient)
+			if err != nil {
+				t.Errorf("IsAllowed() error = %v", err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("IsAllowed() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseOptInKeyword(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantOptIn  bool
+		wantParsed bool
+	}{
+		{
+			name:       "stop",
+			body:       "STOP",
+			wantOptIn:  false,
+			wantParsed: true,
+		},
+		{
+			name:       "lower case unsubscribe with surrounding space",
+			body:       "  unsubscribe ",
+			wantOptIn:  false,
+			wantParsed: true,
+		},
+		{
+			name:       "keyword with trailing text",
+			body:       "unsubscribe please",
+			wantOptIn:  false,
+			wantParsed: false,
+		},
+		{
+			name:       "everyday sentence starting with a keyword",
+			body:       "Cancel my appointment tomorrow",
+			wantOptIn:  false,
+			wantParsed: false,
+		},
+		{
+			name:       "question starting with a keyword",
+			body:       "End of month payment?",
+			wantOptIn:  false,
+			wantParsed: false,
+		},
+		{
+			name:       "swahili stop with punctuation",
+			body:       "Simama!",
+			wantOptIn:  false,
+			wantParsed: true,
+		},
+		{
+			name:       "start",
+			body:       "start",
+			wantOptIn:  true,
+			wantParsed: true,
+		},
+		{
+			name:       "swahili start",
+			body:       "ANZA",
+			wantOptIn:  true,
+			wantParsed: true,
+		},
+		{
+			name:       "keyword not at the start",
+			body:       "please stop",
+			wantOptIn:  false,
+			wantParsed: false,
+		},
+		{
+			name:       "empty message",
+			body:       "   ",
+			wantOptIn:  false,
+			wantParsed: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			optIn, ok := converterandformatter.ParseOptInKeyword(tt.body)
+			if ok != tt.wantParsed {
+				t.Errorf("ParseOptInKeyword() ok = %v, want %v", ok, tt.wantParsed)
+				return
+			}
+			if optIn != tt.wantOptIn {
+				t.Errorf("ParseOptInKeyword() optIn = %v, want %v", optIn, tt.wantOptIn)
+			}
+		})
+	}
+}
+
+func TestHandleOptInSMS_NotAKeyword(t *testing.T) {
+	got, err := converterandformatter.HandleOptInSMS("0722000000", "hello there", nil)
+	assert.Nil(t, err)
+	assert.Nil(t, got)
+
+	_, err = converterandformatter.HandleOptInSMS("not a phone", "STOP", nil)
+	assert.NotNil(t, err)
+}
+
+func TestHandleOptInSMS(t *testing.T) {
+	fc, err := firebasetools.GetFirestoreClient(context.Background())
+	if err != nil {
+		t.Fatalf("unable to initialize Firestore client: %v", err)
+	}
+
+	msisdn := "0722000000"
+	isAllowed := func(topic converterandformatter.OptInTopic) bool {
+		allowed, err := converterandformatter.IsAllowed(
+			msisdn, converterandformatter.OptInChannelSMS, topic, fc)
+		assert.Nil(t, err)
+		return allowed
+	}
+	setPreference := func(topic converterandformatter.OptInTopic, optedIn bool) {
+		_, err := converterandformatter.SetOptInPreference(
+			msisdn, converterandformatter.OptInChannelSMS, topic, optedIn, fc)
+		assert.Nil(t, err)
+	}
+
+	// consented to appointments and health tips, never to marketing
+	setPreference(converterandformatter.OptInTopicAppointments, true)
+	setPreference(converterandformatter.OptInTopicHealthTips, true)
+	setPreference(converterandformatter.OptInTopicMarketing, false)
+
+	got, err := converterandformatter.HandleOptInSMS(msisdn, "STOP", fc)
+	assert.Nil(t, err)
+	assert.NotNil(t, got)
+	assert.False(t, got.OptedIn)
+	assert.Equal(t, "+254722000000", got.MSISDN)
+	for _, topic := range converterandformatter.AllOptInTopics {
+		assert.False(t, isAllowed(topic))
+	}
+
+	got, err = converterandformatter.HandleOptInSMS("+254722000000", "anza", fc)
+	assert.Nil(t, err)
+	assert.NotNil(t, got)
+	assert.True(t, got.OptedIn)
+	assert.True(t, isAllowed(converterandformatter.OptInTopicAppointments))
+	assert.True(t, isAllowed(converterandformatter.OptInTopicHealthTips))
+	assert.False(t, isAllowed(converterandformatter.OptInTopicMarketing))
+
+	// a topic withdrawn deliberately is not restored by START
+	setPreference(converterandformatter.OptInTopicHealthTips, false)
+	_, err = converterandformatter.HandleOptInSMS(msisdn, "STOP", fc)
+	assert.Nil(t, err)
+	_, err = converterandformatter.HandleOptInSMS(msisdn, "START", fc)
+	assert.Nil(t, err)
+	assert.True(t, isAllowed(converterandformatter.OptInTopicAppointments))
+	assert.False(t, isAllowed(converterandformatter.OptInTopicHealthTips))
+}
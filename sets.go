@@ -0,0 +1,155 @@
+package converterandformatter
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// Set is a collection of distinct values that remembers the order in which
+// values were first added.
+//
+// Membership tests take constant time, unlike Contains. A Set is written to
+// JSON and Firestore as an array. The zero value is an empty set ready to use.
+//
+// IMPORTANT: the Firestore client only writes exported struct fields, so it
+// stores a Set passed to it directly, e.g in DocumentRef.Set or
+// SaveDataToFirestore, as an empty map and reads nothing back into one. Save
+// structs that hold sets through StructToFirestoreMap and load them with
+// MapToStruct, which store and read the set as an array that Firestore
+// array-contains queries work on.
+type Set[T comparable] struct {
+	index map[T]int
+	items []T
+}
+
+// StringSet is a set of strings e.g roles or permissions
+type StringSet = Set[string]
+
+// IntSet is a set of ints
+type IntSet = Set[int]
+
+// NewSet initializes a set that holds the supplied items
+func NewSet[T comparable](items ...T) *Set[T] {
+	s := &Set[T]{}
+	s.Add(items...)
+	return s
+}
+
+// Add puts items in the set. Items that are already present keep their
+// position.
+func (s *Set[T]) Add(items ...T) {
+	if s.index == nil {
+		s.index = map[T]int{}
+	}
+	for _, item := range items {
+		if _, ok := s.index[item]; ok {
+			continue
+		}
+		s.index[item] = len(s.items)
+		s.items = append(s.items, item)
+	}
+}
+
+// Remove takes items out of the set
+func (s *Set[T]) Remove(items ...T) {
+	removed := false
+	for _, item := range items {
+		if _, ok := s.index[item]; ok {
+			delete(s.index, item)
+			removed = true
+		}
+	}
+	if !removed {
+		return
+	}
+	kept := make([]T, 0, len(s.index))
+	for _, item := range s.items {
+		if _, ok := s.index[item]; ok {
+			s.index[item] = len(kept)
+			kept = append(kept, item)
+		}
+	}
+	s.items = kept
+}
+
+// Has tests if an item is in the set
+func (s Set[T]) Has(item T) bool {
+	_, ok := s.index[item]
+	return ok
+}
+
+// Len returns the number of items in the set
+func (s Set[T]) Len() int {
+	return len(s.items)
+}
+
+// Items returns the items of the set in the order in which they were added
+func (s Set[T]) Items() []T {
+	return append([]T{}, s.items...)
+}
+
+// Union returns a new set with the items of both sets: the items of s
+// followed by the items of other that are not in s
+func (s Set[T]) Union(other Set[T]) *Set[T] {
+	out := NewSet(s.items...)
+	out.Add(other.items...)
+	return out
+}
+
+// Intersect returns a new set with the items of s that are also in other
+func (s Set[T]) Intersect(other Set[T]) *Set[T] {
+	out := NewSet[T]()
+	for _, item := range s.items {
+		if other.Has(item) {
+			out.Add(item)
+		}
+	}
+	return out
+}
+
+// Difference returns a new set with the items of s that are not in other
+func (s Set[T]) Difference(other Set[T]) *Set[T] {
+	out := NewSet[T]()
+	for _, item := range s.items {
+		if !other.Has(item) {
+			out.Add(item)
+		}
+	}
+	return out
+}
+
+// MarshalJSON writes the set as a JSON array
+func (s Set[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Items())
+}
+
+// UnmarshalJSON reads the set from a JSON array, dropping duplicates
+func (s *Set[T]) UnmarshalJSON(data []byte) error {
+	var items []T
+	err := json.Unmarshal(data, &items)
+	if err != nil {
+		return fmt.Errorf("unable to unmarshal set: %v", err)
+	}
+	*s = Set[T]{}
+	s.Add(items...)
+	return nil
+}
+
+// FirestoreValue returns the items of the set so that it is stored as an
+// array
+func (s Set[T]) FirestoreValue() interface{} {
+	return s.Items()
+}
+
+// LoadFirestoreValue reads the set from an array such as the one returned by
+// FirestoreValue after a round trip through Firestore
+func (s *Set[T]) LoadFirestoreValue(value interface{}) error {
+	var items []T
+	err := decodeValue(value, &items)
+	if err != nil {
+		return fmt.Errorf("unable to load set: %v", err)
+	}
+	*s = Set[T]{}
+	s.Add(items...)
+	return nil
+}
@@ -0,0 +1,142 @@
+package converterandformatter_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	uuid "github.com/kevinburke/go.uuid"
+	"github.com/savannahghi/converterandformatter"
+	"github.com/savannahghi/firebasetools"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSet(t *testing.T) {
+	var roles converterandformatter.StringSet
+	assert.False(t, roles.Has("admin"))
+	assert.Equal(t, 0, roles.Len())
+
+	roles.Add("viewer", "admin", "viewer", "editor")
+	assert.True(t, roles.Has("admin"))
+	assert.Equal(t, 3, roles.Len())
+	assert.Equal(t, []string{"viewer", "admin", "editor"}, roles.Items())
+
+	roles.Remove("admin", "owner")
+	assert.False(t, roles.Has("admin"))
+	assert.Equal(t, []string{"viewer", "editor"}, roles.Items())
+	roles.Add("admin")
+	assert.Equal(t, []string{"viewer", "editor", "admin"}, roles.Items())
+
+	roles.Remove("owner")
+	assert.Equal(t, 3, roles.Len())
+}
+
+func TestSetOperations(t *testing.T) {
+	a := converterandformatter.NewSet(1, 2, 3, 4)
+	b := converterandformatter.NewSet(6, 4, 5, 2)
+
+	assert.Equal(t, []int{1, 2, 3, 4, 6, 5}, a.Union(*b).Items())
+	assert.Equal(t, []int{2, 4}, a.Intersect(*b).Items())
+	assert.Equal(t, []int{1, 3}, a.Difference(*b).Items())
+	assert.Equal(t, []int{6, 5}, b.Difference(*a).Items())
+
+	var empty converterandformatter.IntSet
+	assert.Equal(t, []int{}, a.Intersect(empty).Items())
+	assert.Equal(t, a.Items(), a.Union(empty).Items())
+}
+
+func TestSetJSON(t *testing.T) {
+	type account struct {
+		Roles converterandformatter.StringSet `json:"roles"`
+	}
+	in := account{Roles: *converterandformatter.NewSet("editor", "admin")}
+
+	bs, err := json.Marshal(in)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"roles":["editor","admin"]}`, string(bs))
+
+	var out account
+	err = json.Unmarshal([]byte(`{"roles":["admin","admin","viewer"]}`), &out)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"admin", "viewer"}, out.Roles.Items())
+
+	err = json.Unmarshal([]byte(`{"roles":"admin"}`), &out)
+	assert.NotNil(t, err)
+}
+
+func TestSetFirestore(t *testing.T) {
+	type account struct {
+		Roles *converterandformatter.StringSet `firestore:"roles"`
+		Codes converterandformatter.IntSet     `firestore:"codes"`
+	}
+	in := account{
+		Roles: converterandformatter.NewSet("editor", "admin"),
+		Codes: *converterandformatter.NewSet(3, 1),
+	}
+
+	got, err := converterandformatter.StructToFirestoreMap(in)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"roles": []string{"editor", "admin"},
+		"codes": []int{3, 1},
+	}, got)
+
+	// values as read back from Firestore
+	data := map[string]interface{}{
+		"roles": []interface{}{"editor", "admin"},
+		"codes": []interface{}{int64(3), int64(1), int64(3)},
+	}
+	var out account
+	err = converterandformatter.MapToStruct(
+		data, &out, converterandformatter.WithTagKey(converterandformatter.TagKeyFirestore))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"editor", "admin"}, out.Roles.Items())
+	assert.Equal(t, []int{3, 1}, out.Codes.Items())
+
+	err = converterandformatter.MapToStruct(
+		map[string]interface{}{"codes": []interface{}{"x"}}, &out,
+		converterandformatter.WithTagKey(converterandformatter.TagKeyFirestore))
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "codes: unable to load set")
+}
+
+func TestSet_FirestoreClient(t *testing.T) {
+	type account struct {
+		Roles converterandformatter.StringSet `firestore:"roles"`
+	}
+	ctx := context.Background()
+	fc, err := firebasetools.GetFirestoreClient(ctx)
+	if err != nil {
+		t.Fatalf("unable to initialize Firestore client: %v", err)
+	}
+
+	// the documented path: the client is handed a map, not the struct
+	data, err := converterandformatter.StructToFirestoreMap(
+		account{Roles: *converterandformatter.NewSet("editor", "admin")})
+	assert.Nil(t, err)
+	doc := fc.Collection(converterandformatter.CollectionName("sets_test")).Doc(uuid.NewV4().String())
+	_, err = doc.Set(ctx, data)
+	assert.Nil(t, err)
+	defer func() {
+		_, _ = doc.Delete(ctx)
+	}()
+
+	snapshot, err := doc.Get(ctx)
+	assert.Nil(t, err)
+	assert.Equal(t, []interface{}{"editor", "admin"}, snapshot.Data()["roles"])
+
+	var out account
+	err = converterandformatter.MapToStruct(
+		snapshot.Data(), &out, converterandformatter.WithTagKey(converterandformatter.TagKeyFirestore))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"editor", "admin"}, out.Roles.Items())
+
+	docs, err := fc.Collection(converterandformatter.CollectionName("sets_test")).Where(
+		"roles", "array-contains", "admin").Documents(ctx).GetAll()
+	assert.Nil(t, err)
+	found := false
+	for _, d := range docs {
+		found = found || d.Ref.ID == doc.ID
+	}
+	assert.True(t, found)
+}
@@ -0,0 +1,100 @@
+package converterandformatter
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Contains tests if an element is contained in a slice
+func Contains[T comparable](s []T, e T) bool {
+	return IndexOf(s, e) >= 0
+}
+
+// IndexOf returns the index of the first occurrence of an element in a slice
+// or -1 if the element is not present
+func IndexOf[T comparable](s []T, e T) int {
+	for i, a := range s {
+		if a == e {
+			return i
+		}
+	}
+	return -1
+}
+
+// Unique returns the distinct elements of a slice in the order in which they
+// first occur
+func Unique[T comparable](s []T) []T {
+	seen := make(map[T]struct{}, len(s))
+	out := make([]T, 0, len(s))
+	for _, a := range s {
+		if _, ok := seen[a]; ok {
+			continue
+		}
+		seen[a] = struct{}{}
+		out = append(out, a)
+	}
+	return out
+}
+
+// Map applies a function to every element of a slice and returns the results
+// in the same order
+func Map[T, U any](s []T, f func(T) U) []U {
+	out := make([]U, len(s))
+	for i, a := range s {
+		out[i] = f(a)
+	}
+	return out
+}
+
+// Filter returns the elements of a slice for which keep returns true
+func Filter[T any](s []T, keep func(T) bool) []T {
+	out := []T{}
+	for _, a := range s {
+		if keep(a) {
+			out = append(out, a)
+		}
+	}
+	return out
+}
+
+// Keys returns the keys of a map in no particular order
+func Keys[K comparable, V any](m map[K]V) []K {
+	out := make([]K, 0, len(m))
+	for k := range m {
+		out = append(out, k)
+	}
+	return out
+}
+
+// Values returns the values of a map in no particular order
+func Values[K comparable, V any](m map[K]V) []V {
+	out := make([]V, 0, len(m))
+	for _, v := range m {
+		out = append(out, v)
+	}
+	return out
+}
+
+// ConvertMapValues converts every value of a map with the supplied function.
+//
+// On success the output map is never nil, even for a nil input. When any
+// value fails to convert, a nil map is returned and every failing key is
+// reported in the returned FieldErrors.
+func ConvertMapValues[K comparable, V, W any](
+	m map[K]V, convert func(V) (W, error)) (map[K]W, error) {
+	out := make(map[K]W, len(m))
+	errs := FieldErrors{}
+	for k, v := range m {
+		w, err := convert(v)
+		if err != nil {
+			errs = append(errs, &FieldError{Path: fmt.Sprint(k), Err: err})
+			continue
+		}
+		out[k] = w
+	}
+	if len(errs) > 0 {
+		sort.Slice(errs, func(i, j int) bool { return errs[i].Path < errs[j].Path })
+		return nil, errs
+	}
+	return out, nil
+}
@@ -0,0 +1,69 @@
+package converterandformatter_test
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"testing"
+
+	"github.com/savannahghi/converterandformatter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestContainsAndIndexOf(t *testing.T) {
+	roles := []string{"admin", "editor", "viewer", "editor"}
+	assert.True(t, converterandformatter.Contains(roles, "viewer"))
+	assert.False(t, converterandformatter.Contains(roles, "owner"))
+	assert.Equal(t, 1, converterandformatter.IndexOf(roles, "editor"))
+	assert.Equal(t, -1, converterandformatter.IndexOf(roles, "owner"))
+	assert.False(t, converterandformatter.Contains([]int(nil), 0))
+}
+
+func TestUnique(t *testing.T) {
+	assert.Equal(t, []int{3, 1, 2}, converterandformatter.Unique([]int{3, 1, 3, 2, 1}))
+	assert.Equal(t, []string{}, converterandformatter.Unique([]string(nil)))
+}
+
+func TestMapAndFilter(t *testing.T) {
+	got := converterandformatter.Map([]int{1, 2, 3}, strconv.Itoa)
+	assert.Equal(t, []string{"1", "2", "3"}, got)
+
+	even := converterandformatter.Filter([]int{1, 2, 3, 4}, func(i int) bool {
+		return i%2 == 0
+	})
+	assert.Equal(t, []int{2, 4}, even)
+}
+
+func TestKeysAndValues(t *testing.T) {
+	m := map[string]int{"a": 1, "b": 2}
+
+	keys := converterandformatter.Keys(m)
+	sort.Strings(keys)
+	assert.Equal(t, []string{"a", "b"}, keys)
+
+	values := converterandformatter.Values(m)
+	sort.Ints(values)
+	assert.Equal(t, []int{1, 2}, values)
+}
+
+func TestConvertMapValues(t *testing.T) {
+	got, err := converterandformatter.ConvertMapValues(
+		map[string]string{"a": "1", "b": "2"}, strconv.Atoi)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]int{"a": 1, "b": 2}, got)
+
+	got, err = converterandformatter.ConvertMapValues(map[string]string(nil), strconv.Atoi)
+	assert.Nil(t, err)
+	assert.NotNil(t, got)
+	assert.Empty(t, got)
+
+	failed, err := converterandformatter.ConvertMapValues(
+		map[int]string{2: "x", 1: "y", 3: "3"}, strconv.Atoi)
+	assert.Nil(t, failed)
+	fieldErrs, ok := err.(converterandformatter.FieldErrors)
+	assert.True(t, ok)
+	assert.Len(t, fieldErrs, 2)
+	assert.Equal(t, "1", fieldErrs[0].Path)
+	assert.Equal(t, "2", fieldErrs[1].Path)
+	assert.Contains(t, err.Error(), fmt.Sprintf("1: %v", fieldErrs[0].Err))
+}
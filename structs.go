@@ -0,0 +1,405 @@
+package converterandformatter
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+)
+
+// struct tag keys understood by StructToTypedMap
+const (
+	TagKeyJSON      = "json"
+	TagKeyFirestore = "firestore"
+	TagKeyBSON      = "bson"
+)
+
+var (
+	timeType          = reflect.TypeOf(time.Time{})
+	jsonMarshalerType = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+)
+
+// FirestoreValuer is implemented by types that StructToFirestoreMap writes
+// as a different value e.g Set, which is written as an array
+type FirestoreValuer interface {
+	FirestoreValue() interface{}
+}
+
+// StructMapOption changes how StructToTypedMap and MapToStruct convert
+// between structs and maps
+type StructMapOption func(*structMapConfig)
+
+type structMapConfig struct {
+	tagKey string
+
+	// firestore applies the omitempty and serverTimestamp semantics of the
+	// Firestore client
+	firestore bool
+
+	// weak typing used by MapToStruct
+	integralFloats bool
+	stringNumbers  bool
+	stringTimes    bool
+	timeLayouts    []string
+}
+
+func newStructMapConfig(opts []StructMapOption) structMapConfig {
+	config := structMapConfig{
+		tagKey:      TagKeyJSON,
+		timeLayouts: []string{time.RFC3339Nano},
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return config
+}
+
+// WithTagKey selects the struct tag that names the keys of the output map
+// e.g TagKeyFirestore or TagKeyBSON. The default is TagKeyJSON.
+func WithTagKey(key string) StructMapOption {
+	return func(c *structMapConfig) {
+		c.tagKey = key
+	}
+}
+
+// StructToTypedMap converts a struct (or a pointer to one) to a map without
+// a round trip through JSON, so values keep their Go types: ints stay ints
+// and time.Time, []byte and custom types are not converted.
+//
+// Keys are taken from struct tags the same way encoding/json does: a tag
+// name renames a field, "-" skips it, "omitempty" drops empty values and
+// "string" formats numbers and booleans as strings. Untagged exported
+// embedded structs have their fields promoted into the parent map. Nested structs are
+// converted to nested maps unless they are time.Time or marshal themselves.
+func StructToTypedMap(item interface{}, opts ...StructMapOption) (map[string]interface{}, error) {
+	config := newStructMapConfig(opts)
+	v := reflect.ValueOf(item)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, fmt.Errorf("unable to convert a nil %T to a map", item)
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected a struct, got %T", item)
+	}
+	return structValueToMap(v, config), nil
+}
+
+// StructToFirestoreMap converts a struct (or a pointer to one) to a map
+// keyed by its firestore struct tags, ready to be written with
+// UpdateRecordOnFirestore or a Firestore update.
+//
+// It follows the Firestore client: "omitempty" also drops zero times and a
+// zero time.Time field tagged "serverTimestamp" is replaced with
+// firestore.ServerTimestamp so that the server fills it in. Values that
+// implement FirestoreValuer are written as the value they return.
+func StructToFirestoreMap(item interface{}) (map[string]interface{}, error) {
+	return StructToTypedMap(item, WithTagKey(TagKeyFirestore), func(c *structMapConfig) {
+		c.firestore = true
+	})
+}
+
+func structValueToMap(v reflect.Value, config structMapConfig) map[string]interface{} {
+	out := map[string]interface{}{}
+	for _, f := range structFields(v.Type(), config.tagKey) {
+		fv, ok := fieldByIndex(v, f.index)
+		if !ok {
+			continue
+		}
+		if config.firestore && f.hasOption("serverTimestamp") && isZeroTime(fv) {
+			out[f.name] = firestore.ServerTimestamp
+			continue
+		}
+		if f.omitEmpty && (isEmptyValue(fv) || config.firestore && isZeroTime(fv)) {
+			continue
+		}
+		if f.asString {
+			if s, ok := stringOptionValue(fv); ok {
+				out[f.name] = s
+				continue
+			}
+		}
+		out[f.name] = typedValue(fv, config)
+	}
+	return out
+}
+
+// typedValue returns the value of a field as it should appear in the output
+// map: structs become maps and everything else keeps its type
+func typedValue(v reflect.Value, config structMapConfig) interface{} {
+	if config.firestore && v.IsValid() && v.CanInterface() && !isNilPointer(v) {
+		if valuer, ok := v.Interface().(FirestoreValuer); ok {
+			return valuer.FirestoreValue()
+		}
+	}
+	switch v.Kind() {
+	case reflect.Invalid:
+		return nil
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		if v.Kind() == reflect.Ptr && isConvertibleStruct(v.Type().Elem()) {
+			return structValueToMap(v.Elem(), config)
+		}
+		if v.Kind() == reflect.Interface {
+			return typedValue(v.Elem(), config)
+		}
+	case reflect.Struct:
+		if isConvertibleStruct(v.Type()) {
+			return structValueToMap(v, config)
+		}
+	case reflect.Slice, reflect.Array:
+		if v.Kind() == reflect.Slice && v.IsNil() {
+			return v.Interface()
+		}
+		if containsConvertibleStructs(v.Type().Elem()) {
+			out := make([]interface{}, v.Len())
+			for i := 0; i < v.Len(); i++ {
+				out[i] = typedValue(v.Index(i), config)
+			}
+			return out
+		}
+	case reflect.Map:
+		if !v.IsNil() && v.Type().Key().Kind() == reflect.String &&
+			containsConvertibleStructs(v.Type().Elem()) {
+			out := make(map[string]interface{}, v.Len())
+			iter := v.MapRange()
+			for iter.Next() {
+				out[iter.Key().String()] = typedValue(iter.Value(), config)
+			}
+			return out
+		}
+	}
+	if !v.CanInterface() {
+		return nil
+	}
+	return v.Interface()
+}
+
+// isConvertibleStruct returns true for struct types that are converted to
+// maps rather than kept as they are
+func isConvertibleStruct(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || t == timeType {
+		return false
+	}
+	for _, m := range []reflect.Type{jsonMarshalerType, textMarshalerType} {
+		if t.Implements(m) || reflect.PtrTo(t).Implements(m) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsConvertibleStructs(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Ptr:
+		return isConvertibleStruct(t.Elem())
+	case reflect.Interface:
+		return true
+	}
+	return isConvertibleStruct(t)
+}
+
+// structField describes how a struct field is written to a map
+type structField struct {
+	name      string
+	index     []int
+	depth     int
+	tagged    bool
+	omitEmpty bool
+	asString  bool
+	options   []string
+}
+
+// hasOption returns true if the field's tag carries the supplied option
+func (f structField) hasOption(option string) bool {
+	return Contains(f.options, option)
+}
+
+// structFields lists the fields of a struct type, including the promoted
+// fields of embedded structs, following the precedence rules of
+// encoding/json
+func structFields(t reflect.Type, tagKey string) []structField {
+	candidates := []structField{}
+	collectStructFields(t, tagKey, nil, 0, map[reflect.Type]bool{}, &candidates)
+
+	byName := map[string][]structField{}
+	order := []string{}
+	for _, f := range candidates {
+		if _, ok := byName[f.name]; !ok {
+			order = append(order, f.name)
+		}
+		byName[f.name] = append(byName[f.name], f)
+	}
+	fields := []structField{}
+	for _, name := range order {
+		if f, ok := dominantField(byName[name]); ok {
+			fields = append(fields, f)
+		}
+	}
+	return fields
+}
+
+func collectStructFields(
+	t reflect.Type, tagKey string, index []int, depth int,
+	visited map[reflect.Type]bool, out *[]structField) {
+	visited[t] = true
+	defer delete(visited, t)
+
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		ft := sf.Type
+		if ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if sf.PkgPath != "" {
+			continue // unexported
+		}
+
+		tag := sf.Tag.Get(tagKey)
+		if tag == "-" {
+			continue
+		}
+		name, options := parseTag(tag)
+		fieldIndex := append(append([]int{}, index...), i)
+
+		inline := ft.Kind() == reflect.Struct &&
+			((sf.Anonymous && name == "") || Contains(options, "inline"))
+		if inline {
+			if !visited[ft] {
+				collectStructFields(ft, tagKey, fieldIndex, depth+1, visited, out)
+			}
+			continue
+		}
+		tagged := name != ""
+		if !tagged {
+			name = sf.Name
+			if tagKey == TagKeyBSON {
+				name = strings.ToLower(sf.Name)
+			}
+		}
+		*out = append(*out, structField{
+			name:      name,
+			index:     fieldIndex,
+			depth:     depth,
+			tagged:    tagged,
+			omitEmpty: Contains(options, "omitempty"),
+			asString:  Contains(options, "string"),
+			options:   options,
+		})
+	}
+}
+
+// dominantField picks the field that wins when several fields share a name:
+// the shallowest one, then the tagged one. Ambiguous fields are dropped.
+func dominantField(fields []structField) (structField, bool) {
+	minDepth := fields[0].depth
+	for _, f := range fields {
+		if f.depth < minDepth {
+			minDepth = f.depth
+		}
+	}
+	shallowest := []structField{}
+	for _, f := range fields {
+		if f.depth == minDepth {
+			shallowest = append(shallowest, f)
+		}
+	}
+	if len(shallowest) == 1 {
+		return shallowest[0], true
+	}
+	tagged := []structField{}
+	for _, f := range shallowest {
+		if f.tagged {
+			tagged = append(tagged, f)
+		}
+	}
+	if len(tagged) == 1 {
+		return tagged[0], true
+	}
+	return structField{}, false
+}
+
+func parseTag(tag string) (string, []string) {
+	parts := strings.Split(tag, ",")
+	return parts[0], parts[1:]
+}
+
+// fieldByIndex walks to a possibly promoted field. It returns false when an
+// embedded pointer on the way is nil.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+func isNilPointer(v reflect.Value) bool {
+	return (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil()
+}
+
+// isZeroTime returns true for a zero time.Time or a nil *time.Time
+func isZeroTime(v reflect.Value) bool {
+	if v.Kind() == reflect.Ptr && v.Type().Elem() == timeType {
+		return v.IsNil() || v.Elem().Interface().(time.Time).IsZero()
+	}
+	return v.Type() == timeType && v.Interface().(time.Time).IsZero()
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
+
+// stringOptionValue formats a scalar the way encoding/json does for fields
+// tagged with the "string" option
+func stringOptionValue(v reflect.Value) (string, bool) {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return "", false
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return strconv.Quote(v.String()), true
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(v.Uint(), 10), true
+	case reflect.Float32:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 32), true
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64), true
+	}
+	return "", false
+}
@@ -0,0 +1,166 @@
+package converterandformatter_test
+
+import (
+	"testing"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/savannahghi/converterandformatter"
+	"github.com/stretchr/testify/assert"
+)
+
+type Promoted struct {
+	Shared  string `json:"shared"`
+	Created time.Time
+}
+
+type TypedStruct struct {
+	Promoted
+	*FieldStruct `json:"field,omitempty"`
+
+	Count    int               `json:"count" firestore:"total" bson:"cnt"`
+	Ratio    float32           `json:"ratio,omitempty"`
+	Raw      []byte            `json:"raw"`
+	Shared   string            `json:"shared"`
+	Secret   string            `json:"-"`
+	Dash     string            `json:"-,"`
+	Quoted   int64             `json:"quoted,string"`
+	Samples  []SampleStruct    `json:"samples"`
+	ByName   map[string]string `json:"byName,omitempty"`
+	Optional *int              `json:"optional"`
+	Untagged bool
+	private  string
+}
+
+func TestStructToTypedMap(t *testing.T) {
+	created := time.Date(2021, 7, 1, 9, 30, 0, 0, time.UTC)
+	item := TypedStruct{
+		Promoted: Promoted{Shared: "promoted", Created: created},
+		Count:    3,
+		Raw:      []byte("raw"),
+		Shared:   "outer",
+		Secret:   "hidden",
+		Dash:     "dash",
+		Quoted:   42,
+		Samples:  []SampleStruct{{Name: "John Doe", ID: "12121"}},
+		Untagged: true,
+		private:  "private",
+	}
+
+	got, err := converterandformatter.StructToTypedMap(&item)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"Created":  created,
+		"count":    3,
+		"raw":      []byte("raw"),
+		"shared":   "outer",
+		"-":        "dash",
+		"quoted":   "42",
+		"samples":  []interface{}{map[string]interface{}{"name": "John Doe", "id": "12121"}},
+		"optional": nil,
+		"Untagged": true,
+	}, got)
+}
+
+func TestStructToTypedMap_NestedStructs(t *testing.T) {
+	embed := EmbededStruct{
+		FieldStruct: FieldStruct{
+			OnePoint: "yuhuhuu",
+			Sample:   &SampleStruct{Name: "John Doe", ID: "12121"},
+		},
+		Hello: "WORLD!!!!",
+	}
+
+	got, err := converterandformatter.StructToTypedMap(embed)
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"field": map[string]interface{}{
+			"one_point": "yuhuhuu",
+			"sample":    map[string]interface{}{"name": "John Doe", "id": "12121"},
+		},
+		"hello": "WORLD!!!!",
+	}, got)
+}
+
+func TestStructToTypedMap_TagKeys(t *testing.T) {
+	item := TypedStruct{Count: 7}
+
+	got, err := converterandformatter.StructToTypedMap(
+		item, converterandformatter.WithTagKey(converterandformatter.TagKeyFirestore))
+	assert.Nil(t, err)
+	assert.Equal(t, 7, got["total"])
+	assert.Contains(t, got, "Shared")
+
+	got, err = converterandformatter.StructToTypedMap(
+		item, converterandformatter.WithTagKey(converterandformatter.TagKeyBSON))
+	assert.Nil(t, err)
+	assert.Equal(t, 7, got["cnt"])
+	assert.Contains(t, got, "untagged")
+}
+
+func TestStructToTypedMap_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		item interface{}
+	}{
+		{
+			name: "not a struct",
+			item: make(chan string),
+		},
+		{
+			name: "nil pointer",
+			item: (*SampleStruct)(nil),
+		},
+		{
+			name: "nil",
+			item: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := converterandformatter.StructToTypedMap(tt.item)
+			assert.NotNil(t, err)
+			assert.Nil(t, got)
+		})
+	}
+}
+
+func TestStructToFirestoreMap(t *testing.T) {
+	type record struct {
+		DisplayName string     `json:"displayName" firestore:"display_name"`
+		Nickname    string     `json:"nickname" firestore:"nickname,omitempty"`
+		Created     time.Time  `json:"created" firestore:"created,serverTimestamp"`
+		Updated     time.Time  `json:"updated" firestore:"updated,serverTimestamp"`
+		Seen        *time.Time `json:"seen" firestore:"seen,omitempty"`
+		Deleted     time.Time  `json:"deleted" firestore:"deleted,omitempty"`
+		Internal    string     `json:"internal" firestore:"-"`
+		Count       int
+	}
+	updated := time.Date(2021, 7, 1, 9, 30, 0, 0, time.UTC)
+
+	got, err := converterandformatter.StructToFirestoreMap(record{
+		DisplayName: "Wanjiku",
+		Updated:     updated,
+		Internal:    "internal",
+		Count:       2,
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"display_name": "Wanjiku",
+		"created":      firestore.ServerTimestamp,
+		"updated":      updated,
+		"Count":        2,
+	}, got)
+}
+
+func TestStructToFirestoreMap_Models(t *testing.T) {
+	session, err := converterandformatter.NewUSSDSession(
+		"ATUid_123", "0722000000", "*384*123#", "63902")
+	assert.Nil(t, err)
+
+	got, err := converterandformatter.StructToFirestoreMap(session)
+	assert.Nil(t, err)
+	assert.Equal(t, "+254722000000", got["msisdn"])
+	assert.Equal(t, converterandformatter.USSDSessionStatusActive, got["status"])
+	assert.NotContains(t, got, "endedAt")
+}
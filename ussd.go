@@ -0,0 +1,196 @@
+package converterandformatter
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"cloud.google.com/go/firestore"
+	"github.com/savannahghi/firebasetools"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// NewUSSDSession validates the details of a newly dialled USSD session and
+// returns an active session for the normalized phone number
+func NewUSSDSession(
+	sessionID, msisdn, serviceCode, networkCode string) (*USSDSession, error) {
+	if strings.TrimSpace(sessionID) == "" {
+		return nil, fmt.Errorf("a USSD session ID is required")
+	}
+	normalized, err := NormalizeMSISDN(msisdn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid phone format: %v", err)
+	}
+	now := time.Now()
+	return &USSDSession{
+		SessionID:   sessionID,
+		MSISDN:      *normalized,
+		ServiceCode: serviceCode,
+		NetworkCode: networkCode,
+		Inputs:      []string{},
+		Status:      USSDSessionStatusActive,
+		StartedAt:   now,
+		UpdatedAt:   now,
+	}, nil
+}
+
+// StartUSSDSession creates and persists a new active USSD session.
+//
+// The session ID supplied by the telco is used as the document ID so that
+// later updates address the same record. Starting a session whose ID has
+// already been recorded fails rather than overwriting it.
+func StartUSSDSession(
+	sessionID, msisdn, serviceCode, networkCode string,
+	firestoreClient *firestore.Client) (*USSDSession, error) {
+	session, err := NewUSSDSession(sessionID, msisdn, serviceCode, networkCode)
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	_, err = firestoreClient.Collection(
+		CollectionName(USSDSessionCollectionName)).Doc(sessionID).Create(ctx, session)
+	if err != nil {
+		if status.Code(err) == codes.AlreadyExists {
+			return nil, fmt.Errorf("USSD session %s has already been started", sessionID)
+		}
+		return nil, fmt.Errorf("unable to save USSD session: %v", err)
+	}
+	return session, nil
+}
+
+// GetUSSDSession retrieves a persisted USSD session by its session ID
+func GetUSSDSession(
+	sessionID string, firestoreClient *firestore.Client) (*USSDSession, error) {
+	ctx := context.Background()
+	doc, err := firestoreClient.Collection(
+		CollectionName(USSDSessionCollectionName)).Doc(sessionID).Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve USSD session: %v", err)
+	}
+	var session USSDSession
+	err = doc.DataTo(&session)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read USSD session: %v", err)
+	}
+	return &session, nil
+}
+
+// UpdateUSSDSession moves an active USSD session to a new menu state and
+// records the input (if any) that the user supplied to get there
+func UpdateUSSDSession(
+	sessionID, menuState, input string,
+	firestoreClient *firestore.Client) (*USSDSession, error) {
+	session, err := GetUSSDSession(sessionID, firestoreClient)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status != USSDSessionStatusActive {
+		return nil, fmt.Errorf(
+			"USSD session %s is %s and can not be updated", sessionID, session.Status)
+	}
+	session.MenuState = menuState
+	if input != "" {
+		session.Inputs = append(session.Inputs, input)
+	}
+	session.UpdatedAt = time.Now()
+	err = saveUSSDSession(session, firestoreClient)
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// EndUSSDSession closes an active USSD session with the supplied final status
+func EndUSSDSession(
+	sessionID string, status USSDSessionStatus,
+	firestoreClient *firestore.Client) (*USSDSession, error) {
+	if !status.IsValid() || status == USSDSessionStatusActive {
+		return nil, fmt.Errorf("invalid final USSD session status: %s", status)
+	}
+	session, err := GetUSSDSession(sessionID, firestoreClient)
+	if err != nil {
+		return nil, err
+	}
+	if session.Status != USSDSessionStatusActive {
+		return nil, fmt.Errorf("USSD session %s has already ended", sessionID)
+	}
+	now := time.Now()
+	session.Status = status
+	session.UpdatedAt = now
+	session.EndedAt = &now
+	err = saveUSSDSession(session, firestoreClient)
+	if err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+func saveUSSDSession(session *USSDSession, firestoreClient *firestore.Client) error {
+	err := firebasetools.UpdateRecordOnFirestore(
+		firestoreClient, CollectionName(USSDSessionCollectionName),
+		session.SessionID, session)
+	if err != nil {
+		return fmt.Errorf("unable to save USSD session: %v", err)
+	}
+	return nil
+}
+
+// USSDSessionVerificationWindow is how long after its last recorded activity
+// a USSD session can still be used to verify the phone number that dialled it
+const USSDSessionVerificationWindow = 5 * time.Minute
+
+// VerifyAt checks that the session is still active, was dialled by the
+// supplied (normalized) phone number and has had activity within
+// USSDSessionVerificationWindow of the supplied time
+func (s USSDSession) VerifyAt(msisdn string, at time.Time) error {
+	if s.MSISDN != msisdn {
+		return fmt.Errorf("no matching USSD session found")
+	}
+	if s.Status != USSDSessionStatusActive {
+		return fmt.Errorf("USSD session %s is %s", s.SessionID, s.Status)
+	}
+	if at.Sub(s.UpdatedAt) > USSDSessionVerificationWindow {
+		return fmt.Errorf("USSD session %s has expired", s.SessionID)
+	}
+	return nil
+}
+
+// VerifyUSSDSession confirms that a USSD session with the supplied ID is
+// still active and that it was dialled by the supplied phone number. It
+// returns the normalized phone number.
+//
+// Only sessions recorded by StartUSSDSession, which are checked with
+// USSDSession.VerifyAt, are recognised. USSDSessionLog records carry no
+// status or timestamps to check, so they are not accepted.
+func VerifyUSSDSession(
+	sessionID, msisdn string, firestoreClient *firestore.Client) (string, error) {
+	normalized, err := NormalizeMSISDN(msisdn)
+	if err != nil {
+		return "", fmt.Errorf("invalid phone format: %v", err)
+	}
+	if strings.TrimSpace(sessionID) == "" {
+		return "", fmt.Errorf("a USSD session ID is required")
+	}
+
+	ctx := context.Background()
+	doc, err := firestoreClient.Collection(
+		CollectionName(USSDSessionCollectionName)).Doc(sessionID).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return "", fmt.Errorf("no matching USSD session found")
+	}
+	if err != nil {
+		return "", fmt.Errorf("unable to retrieve USSD session: %v", err)
+	}
+	var session USSDSession
+	err = doc.DataTo(&session)
+	if err != nil {
+		return "", fmt.Errorf("unable to read USSD session: %v", err)
+	}
+	err = session.VerifyAt(*normalized, time.Now())
+	if err != nil {
+		return "", err
+	}
+	return *normalized, nil
+}
@@ -0,0 +1,139 @@
+package converterandformatter
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// USSDMaxResponseLength is the maximum number of characters that fit on a
+// single USSD screen
+const USSDMaxResponseLength = 182
+
+// USSD response prefixes understood by Africa's Talking style gateways
+const (
+	ussdContinuePrefix = "CON "
+	ussdEndPrefix      = "END "
+)
+
+// USSDInputSeparator separates the inputs a user has entered in the text
+// field of a USSD gateway request
+const USSDInputSeparator = "*"
+
+// USSDRequest is a USSD gateway callback e.g from Africa's Talking
+type USSDRequest struct {
+	SessionID   string
+	ServiceCode string
+	NetworkCode string
+
+	// PhoneNumber is the normalized MSISDN that dialled the service
+	PhoneNumber string
+
+	// Text is the raw text sent by the gateway e.g "1*2*0722000000"
+	Text string
+
+	// Inputs is Text split into the individual inputs the user has entered
+	// since the session started, oldest first
+	Inputs []string
+}
+
+// IsInitial returns true if the request is the first one of a session i.e
+// the user has just dialled the service code and not entered anything
+func (r USSDRequest) IsInitial() bool {
+	return len(r.Inputs) == 0
+}
+
+// LastInput returns the most recent input entered by the user or an empty
+// string if nothing has been entered yet
+func (r USSDRequest) LastInput() string {
+	if r.IsInitial() {
+		return ""
+	}
+	return r.Inputs[len(r.Inputs)-1]
+}
+
+// ParseUSSDRequest reads a USSD gateway request from form values
+// (sessionId, serviceCode, networkCode, phoneNumber and text).
+//
+// The phone number is normalized with NormalizeMSISDN and the text is split
+// into the inputs entered so far.
+func ParseUSSDRequest(values url.Values) (*USSDRequest, error) {
+	sessionID := strings.TrimSpace(values.Get("sessionId"))
+	if sessionID == "" {
+		return nil, fmt.Errorf("a USSD session ID is required")
+	}
+	serviceCode := strings.TrimSpace(values.Get("serviceCode"))
+	if serviceCode == "" {
+		return nil, fmt.Errorf("a USSD service code is required")
+	}
+	normalized, err := NormalizeMSISDN(strings.TrimSpace(values.Get("phoneNumber")))
+	if err != nil {
+		return nil, fmt.Errorf("invalid phone format: %v", err)
+	}
+	text := values.Get("text")
+	inputs := []string{}
+	if text != "" {
+		inputs = strings.Split(text, USSDInputSeparator)
+	}
+	return &USSDRequest{
+		SessionID:   sessionID,
+		ServiceCode: serviceCode,
+		NetworkCode: strings.TrimSpace(values.Get("networkCode")),
+		PhoneNumber: *normalized,
+		Text:        text,
+		Inputs:      inputs,
+	}, nil
+}
+
+// ParseUSSDHTTPRequest parses the form posted by a USSD gateway and reads
+// the USSD request from it
+func ParseUSSDHTTPRequest(r *http.Request) (*USSDRequest, error) {
+	err := r.ParseForm()
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse USSD request form: %v", err)
+	}
+	return ParseUSSDRequest(r.Form)
+}
+
+// USSDResponse is the reply to a USSD gateway request
+type USSDResponse struct {
+	// Message is the text shown on the user's screen
+	Message string
+
+	// End is true if the session ends after the message is shown. Otherwise
+	// the user is prompted for more input.
+	End bool
+}
+
+// ContinueUSSD returns a response that shows a message and waits for input
+func ContinueUSSD(message string) USSDResponse {
+	return USSDResponse{Message: message}
+}
+
+// EndUSSD returns a response that shows a message and ends the session
+func EndUSSD(message string) USSDResponse {
+	return USSDResponse{Message: message, End: true}
+}
+
+// Format renders the response in the form expected by the gateway i.e
+// prefixed with "CON" or "END".
+//
+// An error is returned if the message does not fit on a single screen or if a
+// response that waits for input has no message to prompt with.
+func (r USSDResponse) Format() (string, error) {
+	length := utf8.RuneCountInString(r.Message)
+	if length > USSDMaxResponseLength {
+		return "", fmt.Errorf(
+			"USSD message has %d characters, the maximum is %d",
+			length, USSDMaxResponseLength)
+	}
+	if r.End {
+		return ussdEndPrefix + r.Message, nil
+	}
+	if strings.TrimSpace(r.Message) == "" {
+		return "", fmt.Errorf("a USSD message that waits for input can not be empty")
+	}
+	return ussdContinuePrefix + r.Message, nil
+}
@@ -0,0 +1,161 @@
+package converterandformatter_test
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/savannahghi/converterandformatter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseUSSDRequest(t *testing.T) {
+	tests := []struct {
+		name       string
+		values     url.Values
+		wantInputs []string
+		wantErr    bool
+	}{
+		{
+			name: "initial dial",
+			values: url.Values{
+				"sessionId":   {"ATUid_1"},
+				"serviceCode": {"*384*123#"},
+				"networkCode": {"63902"},
+				"phoneNumber": {"0722000000"},
+				"text":        {""},
+			},
+			wantInputs: []string{},
+			wantErr:    false,
+		},
+		{
+			name: "several inputs",
+			values: url.Values{
+				"sessionId":   {"ATUid_1"},
+				"serviceCode": {"*384*123#"},
+				"phoneNumber": {"+254722000000"},
+				"text":        {"1*2*0711000000"},
+			},
+			wantInputs: []string{"1", "2", "0711000000"},
+			wantErr:    false,
+		},
+		{
+			name: "missing session ID",
+			values: url.Values{
+				"serviceCode": {"*384*123#"},
+				"phoneNumber": {"0722000000"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "missing service code",
+			values: url.Values{
+				"sessionId":   {"ATUid_1"},
+				"phoneNumber": {"0722000000"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid phone number",
+			values: url.Values{
+				"sessionId":   {"ATUid_1"},
+				"serviceCode": {"*384*123#"},
+				"phoneNumber": {"not a phone"},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := converterandformatter.ParseUSSDRequest(tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseUSSDRequest() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			assert.Equal(t, "+254722000000", got.PhoneNumber)
+			assert.Equal(t, tt.wantInputs, got.Inputs)
+		})
+	}
+}
+
+func TestUSSDRequestInputs(t *testing.T) {
+	initial := converterandformatter.USSDRequest{Inputs: []string{}}
+	assert.True(t, initial.IsInitial())
+	assert.Equal(t, "", initial.LastInput())
+
+	later := converterandformatter.USSDRequest{Inputs: []string{"1", "3"}}
+	assert.False(t, later.IsInitial())
+	assert.Equal(t, "3", later.LastInput())
+}
+
+func TestParseUSSDHTTPRequest(t *testing.T) {
+	form := url.Values{
+		"sessionId":   {"ATUid_1"},
+		"serviceCode": {"*384*123#"},
+		"phoneNumber": {"0722000000"},
+		"text":        {"1"},
+	}
+	r, err := http.NewRequest(http.MethodPost, "/ussd", strings.NewReader(form.Encode()))
+	assert.Nil(t, err)
+	r.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	got, err := converterandformatter.ParseUSSDHTTPRequest(r)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"1"}, got.Inputs)
+}
+
+func TestUSSDResponseFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		response converterandformatter.USSDResponse
+		want     string
+		wantErr  bool
+	}{
+		{
+			name:     "continue",
+			response: converterandformatter.ContinueUSSD("1. Register\n2. Exit"),
+			want:     "CON 1. Register\n2. Exit",
+		},
+		{
+			name:     "end",
+			response: converterandformatter.EndUSSD("Thank you"),
+			want:     "END Thank you",
+		},
+		{
+			name:     "end without a message",
+			response: converterandformatter.EndUSSD(""),
+			want:     "END ",
+		},
+		{
+			name:     "continue without a message",
+			response: converterandformatter.ContinueUSSD(" "),
+			wantErr:  true,
+		},
+		{
+			name:     "message at the screen limit",
+			response: converterandformatter.ContinueUSSD(strings.Repeat("ü", 182)),
+			want:     "CON " + strings.Repeat("ü", 182),
+		},
+		{
+			name:     "message over the screen limit",
+			response: converterandformatter.EndUSSD(strings.Repeat("a", 183)),
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.response.Format()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Format() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Format() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
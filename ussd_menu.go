@@ -0,0 +1,607 @@
+package converterandformatter
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"cloud.google.com/go/firestore"
+	"github.com/savannahghi/firebasetools"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// default navigation inputs and page size of a USSD menu
+const (
+	DefaultUSSDBackInput = "0"
+	DefaultUSSDHomeInput = "00"
+	DefaultUSSDMoreInput = "98"
+	DefaultUSSDPageSize  = 5
+
+	ussdInvalidChoiceMessage = "Invalid choice."
+)
+
+// USSDInputValidator checks a free text input entered on a USSD screen.
+//
+// The message of a returned error is shown to the user above the screen,
+// which is then displayed again.
+type USSDInputValidator func(input string) error
+
+// USSDPredicateValidator adapts a predicate such as IsMSISDNValid into a
+// USSDInputValidator that fails with the supplied message
+func USSDPredicateValidator(
+	predicate func(input string) bool, message string) USSDInputValidator {
+	return func(input string) error {
+		if !predicate(input) {
+			return fmt.Errorf("%s", message)
+		}
+		return nil
+	}
+}
+
+// USSDOption is a numbered choice on a USSD screen
+type USSDOption struct {
+	// Label is the text shown next to the option number
+	Label string
+
+	// Value is stored under the screen's Key when the option is chosen. The
+	// label is stored when the value is empty.
+	Value string
+
+	// Next is the ID of the screen shown when the option is chosen
+	Next string
+}
+
+// USSDScreen is a single step of a declarative USSD menu.
+//
+// A screen either offers numbered options, asks for free text input or,
+// when End is true, shows a final message and closes the session.
+type USSDScreen struct {
+	ID string
+
+	// Text is shown at the top of the screen. Placeholders such as {name}
+	// are replaced with the values captured so far in the session.
+	Text string
+
+	// Options are the numbered choices offered on the screen. Long lists are
+	// paginated so that each page fits in USSDMaxResponseLength characters.
+	Options []USSDOption
+
+	// LoadOptions, when set, is used instead of Options to build the list of
+	// choices from the values captured so far
+	LoadOptions func(values map[string]string) ([]USSDOption, error)
+
+	// Key is the name under which the chosen option or the free text input
+	// is stored in the session values
+	Key string
+
+	// Validate checks free text input before it is accepted
+	Validate USSDInputValidator
+
+	// Next is the ID of the screen shown after valid free text input
+	Next string
+
+	// OnEnter is called every time the screen is entered from another screen,
+	// including when the user returns to it with the back or home inputs
+	OnEnter func(state *USSDMenuState) error
+
+	// DisableNavigation treats the back and home inputs as ordinary input on
+	// this screen e.g where "0" is a valid amount. The back and home choices
+	// are not shown.
+	DisableNavigation bool
+
+	// End marks a final screen that closes the session
+	End bool
+}
+
+// USSDMenuState is the position of a USSD session in a menu
+type USSDMenuState struct {
+	SessionID   string            `json:"sessionID" firestore:"sessionID"`
+	PhoneNumber string            `json:"phoneNumber" firestore:"phoneNumber"`
+	ScreenID    string            `json:"screenID" firestore:"screenID"`
+	History     []string          `json:"history" firestore:"history"`
+	Page        int               `json:"page" firestore:"page"`
+	Values      map[string]string `json:"values" firestore:"values"`
+
+	// Processed is the number of inputs in the gateway's text that have
+	// already been applied to the state
+	Processed int       `json:"processed" firestore:"processed"`
+	UpdatedAt time.Time `json:"updatedAt" firestore:"updatedAt"`
+}
+
+// USSDMenuStore persists USSD menu state between gateway requests
+type USSDMenuStore interface {
+	// Get returns the state of a session or nil if the session has no state
+	Get(sessionID string) (*USSDMenuState, error)
+	Save(state *USSDMenuState) error
+	Delete(sessionID string) error
+}
+
+// InMemoryUSSDMenuStore keeps USSD menu state in memory. It is meant for
+// tests and single instance deployments.
+type InMemoryUSSDMenuStore struct {
+	mu     sync.Mutex
+	states map[string]USSDMenuState
+}
+
+// NewInMemoryUSSDMenuStore initializes an empty in memory USSD menu store
+func NewInMemoryUSSDMenuStore() *InMemoryUSSDMenuStore {
+	return &InMemoryUSSDMenuStore{states: map[string]USSDMenuState{}}
+}
+
+// Get returns a copy of the state of a session
+func (s *InMemoryUSSDMenuStore) Get(sessionID string) (*USSDMenuState, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	state, ok := s.states[sessionID]
+	if !ok {
+		return nil, nil
+	}
+	return copyUSSDMenuState(state), nil
+}
+
+// Save stores a copy of the state of a session
+func (s *InMemoryUSSDMenuStore) Save(state *USSDMenuState) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.states[state.SessionID] = *copyUSSDMenuState(*state)
+	return nil
+}
+
+// Delete removes the state of a session
+func (s *InMemoryUSSDMenuStore) Delete(sessionID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.states, sessionID)
+	return nil
+}
+
+func copyUSSDMenuState(state USSDMenuState) *USSDMenuState {
+	state.History = append([]string{}, state.History...)
+	values := make(map[string]string, len(state.Values))
+	for k, v := range state.Values {
+		values[k] = v
+	}
+	state.Values = values
+	return &state
+}
+
+// FirestoreUSSDMenuStore keeps USSD menu state in the
+// USSDMenuStateCollectionName collection
+type FirestoreUSSDMenuStore struct {
+	firestoreClient *firestore.Client
+}
+
+// NewFirestoreUSSDMenuStore initializes a Firestore backed USSD menu store
+func NewFirestoreUSSDMenuStore(firestoreClient *firestore.Client) *FirestoreUSSDMenuStore {
+	return &FirestoreUSSDMenuStore{firestoreClient: firestoreClient}
+}
+
+// Get retrieves the state of a session from Firestore
+func (s *FirestoreUSSDMenuStore) Get(sessionID string) (*USSDMenuState, error) {
+	ctx := context.Background()
+	doc, err := s.firestoreClient.Collection(
+		CollectionName(USSDMenuStateCollectionName)).Doc(sessionID).Get(ctx)
+	if status.Code(err) == codes.NotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to retrieve USSD menu state: %v", err)
+	}
+	var state USSDMenuState
+	err = doc.DataTo(&state)
+	if err != nil {
+		return nil, fmt.Errorf("unable to read USSD menu state: %v", err)
+	}
+	return &state, nil
+}
+
+// Save writes the state of a session to Firestore
+func (s *FirestoreUSSDMenuStore) Save(state *USSDMenuState) error {
+	err := firebasetools.UpdateRecordOnFirestore(
+		s.firestoreClient, CollectionName(USSDMenuStateCollectionName),
+		state.SessionID, state)
+	if err != nil {
+		return fmt.Errorf("unable to save USSD menu state: %v", err)
+	}
+	return nil
+}
+
+// Delete removes the state of a session from Firestore
+func (s *FirestoreUSSDMenuStore) Delete(sessionID string) error {
+	ctx := context.Background()
+	_, err := s.firestoreClient.Collection(
+		CollectionName(USSDMenuStateCollectionName)).Doc(sessionID).Delete(ctx)
+	if err != nil {
+		return fmt.Errorf("unable to delete USSD menu state: %v", err)
+	}
+	return nil
+}
+
+// USSDMenu is a declarative USSD menu: a set of screens joined by
+// transitions that is driven by parsed USSD gateway requests.
+//
+// The navigation inputs and the page size can be changed after the menu has
+// been initialized.
+type USSDMenu struct {
+	Start   string
+	Screens map[string]*USSDScreen
+	Store   USSDMenuStore
+
+	BackInput string
+	HomeInput string
+	MoreInput string
+
+	// PageSize is the most options shown on a page. Fewer are shown when
+	// their labels would not fit in USSDMaxResponseLength characters.
+	PageSize int
+}
+
+// NewUSSDMenu initializes a USSD menu that starts at the screen with the
+// supplied ID.
+//
+// It returns an error if a screen ID is repeated or if a transition points
+// to a screen that does not exist.
+func NewUSSDMenu(
+	start string, store USSDMenuStore, screens ...*USSDScreen) (*USSDMenu, error) {
+	if store == nil {
+		return nil, fmt.Errorf("a USSD menu store is required")
+	}
+	byID := map[string]*USSDScreen{}
+	for _, screen := range screens {
+		if screen.ID == "" {
+			return nil, fmt.Errorf("a USSD screen ID is required")
+		}
+		if _, ok := byID[screen.ID]; ok {
+			return nil, fmt.Errorf("duplicate USSD screen ID: %s", screen.ID)
+		}
+		byID[screen.ID] = screen
+	}
+	if _, ok := byID[start]; !ok {
+		return nil, fmt.Errorf("unknown USSD start screen: %s", start)
+	}
+	for _, screen := range screens {
+		if screen.Next != "" {
+			if _, ok := byID[screen.Next]; !ok {
+				return nil, fmt.Errorf(
+					"USSD screen %s leads to unknown screen %s", screen.ID, screen.Next)
+			}
+		}
+		for _, option := range screen.Options {
+			if _, ok := byID[option.Next]; !ok {
+				return nil, fmt.Errorf(
+					"USSD screen %s option %q leads to unknown screen %s",
+					screen.ID, option.Label, option.Next)
+			}
+		}
+	}
+	return &USSDMenu{
+		Start:     start,
+		Screens:   byID,
+		Store:     store,
+		BackInput: DefaultUSSDBackInput,
+		HomeInput: DefaultUSSDHomeInput,
+		MoreInput: DefaultUSSDMoreInput,
+		PageSize:  DefaultUSSDPageSize,
+	}, nil
+}
+
+// Handle applies the inputs of a USSD gateway request that have not been
+// seen before to the session's menu state and returns the next screen
+func (m *USSDMenu) Handle(req *USSDRequest) (USSDResponse, error) {
+	state, err := m.Store.Get(req.SessionID)
+	if err != nil {
+		return USSDResponse{}, err
+	}
+	if state == nil {
+		state = &USSDMenuState{
+			SessionID:   req.SessionID,
+			PhoneNumber: req.PhoneNumber,
+			ScreenID:    m.Start,
+			History:     []string{},
+			Values:      map[string]string{},
+		}
+		err = m.enter(state)
+		if err != nil {
+			return USSDResponse{}, err
+		}
+	}
+
+	notice := ""
+	if state.Processed < len(req.Inputs) {
+		for _, input := range req.Inputs[state.Processed:] {
+			notice, err = m.apply(state, strings.TrimSpace(input))
+			if err != nil {
+				return USSDResponse{}, err
+			}
+		}
+	}
+	state.Processed = len(req.Inputs)
+	state.UpdatedAt = time.Now()
+
+	screen, err := m.screen(state.ScreenID)
+	if err != nil {
+		return USSDResponse{}, err
+	}
+	text, err := m.render(screen, state)
+	if err != nil {
+		return USSDResponse{}, err
+	}
+	if notice != "" {
+		text = withUSSDNotice(notice, text)
+	}
+	if screen.End {
+		err = m.Store.Delete(state.SessionID)
+		if err != nil {
+			return USSDResponse{}, err
+		}
+		return EndUSSD(text), nil
+	}
+	err = m.Store.Save(state)
+	if err != nil {
+		return USSDResponse{}, err
+	}
+	return ContinueUSSD(text), nil
+}
+
+// apply moves the state in response to a single input. The returned notice
+// is shown to the user when the input was rejected.
+func (m *USSDMenu) apply(state *USSDMenuState, input string) (string, error) {
+	screen, err := m.screen(state.ScreenID)
+	if err != nil {
+		return "", err
+	}
+	if screen.End {
+		return "", nil
+	}
+	if !screen.DisableNavigation {
+		if input == m.HomeInput && state.ScreenID != m.Start {
+			state.History = []string{}
+			state.ScreenID = m.Start
+			state.Page = 0
+			return "", m.enter(state)
+		}
+		if input == m.BackInput && len(state.History) > 0 {
+			state.ScreenID = state.History[len(state.History)-1]
+			state.History = state.History[:len(state.History)-1]
+			state.Page = 0
+			return "", m.enter(state)
+		}
+	}
+
+	options, err := m.options(screen, state)
+	if err != nil {
+		return "", err
+	}
+	if len(options) > 0 {
+		if input == m.MoreInput && state.Page+1 < len(m.pages(screen, state, options)) {
+			state.Page++
+			return "", nil
+		}
+		choice, err := strconv.Atoi(input)
+		if err != nil || choice < 1 || choice > len(options) {
+			return ussdInvalidChoiceMessage, nil
+		}
+		option := options[choice-1]
+		if screen.Key != "" {
+			value := option.Value
+			if value == "" {
+				value = option.Label
+			}
+			state.Values[screen.Key] = value
+		}
+		return "", m.transition(state, option.Next)
+	}
+
+	if screen.Validate != nil {
+		err = screen.Validate(input)
+		if err != nil {
+			return err.Error(), nil
+		}
+	}
+	if screen.Key != "" {
+		state.Values[screen.Key] = input
+	}
+	if screen.Next == "" {
+		return "", nil
+	}
+	return "", m.transition(state, screen.Next)
+}
+
+func (m *USSDMenu) transition(state *USSDMenuState, next string) error {
+	state.History = append(state.History, state.ScreenID)
+	state.ScreenID = next
+	state.Page = 0
+	return m.enter(state)
+}
+
+func (m *USSDMenu) enter(state *USSDMenuState) error {
+	screen, err := m.screen(state.ScreenID)
+	if err != nil {
+		return err
+	}
+	if screen.OnEnter == nil {
+		return nil
+	}
+	return screen.OnEnter(state)
+}
+
+func (m *USSDMenu) screen(id string) (*USSDScreen, error) {
+	screen, ok := m.Screens[id]
+	if !ok {
+		return nil, fmt.Errorf("unknown USSD screen: %s", id)
+	}
+	return screen, nil
+}
+
+func (m *USSDMenu) options(screen *USSDScreen, state *USSDMenuState) ([]USSDOption, error) {
+	if screen.LoadOptions == nil {
+		return screen.Options, nil
+	}
+	options, err := screen.LoadOptions(state.Values)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load options for USSD screen %s: %v", screen.ID, err)
+	}
+	for _, option := range options {
+		if _, ok := m.Screens[option.Next]; !ok {
+			return nil, fmt.Errorf(
+				"USSD screen %s option %q leads to unknown screen %s",
+				screen.ID, option.Label, option.Next)
+		}
+	}
+	return options, nil
+}
+
+func (m *USSDMenu) pageSize() int {
+	if m.PageSize < 1 {
+		return DefaultUSSDPageSize
+	}
+	return m.PageSize
+}
+
+// pages returns the index of the first option on each page of a screen.
+//
+// A page holds at most PageSize options and, when possible, no more than fit
+// in USSDMaxResponseLength characters along with the screen text, the more
+// choice, the navigation choices and the invalid choice notice that is shown
+// above the page when a wrong key is pressed. A page always holds at least
+// one option.
+func (m *USSDMenu) pages(
+	screen *USSDScreen, state *USSDMenuState, options []USSDOption) []int {
+	header := m.header(screen, state)
+	footer := m.footer(screen, state)
+	pages := []int{}
+	for start := 0; start < len(options); {
+		pages = append(pages, start)
+		end := start + 1
+		for end < len(options) && end-start < m.pageSize() {
+			lines := append(append([]string{}, header...), m.optionLines(options, start, end+1)...)
+			lines = append(lines, footer...)
+			length := utf8.RuneCountInString(ussdInvalidChoiceMessage+"\n") +
+				utf8.RuneCountInString(strings.Join(lines, "\n"))
+			if length > USSDMaxResponseLength {
+				break
+			}
+			end++
+		}
+		start = end
+	}
+	return pages
+}
+
+// withUSSDNotice puts a notice above the text of a screen, shortening the
+// notice, or leaving it out, when both would not fit in
+// USSDMaxResponseLength characters
+func withUSSDNotice(notice, text string) string {
+	room := USSDMaxResponseLength - utf8.RuneCountInString(text) - 1
+	if room <= 0 {
+		return text
+	}
+	if runes := []rune(notice); len(runes) > room {
+		notice = string(runes[:room])
+	}
+	return notice + "\n" + text
+}
+
+// header returns the text of a screen with its placeholders filled in
+func (m *USSDMenu) header(screen *USSDScreen, state *USSDMenuState) []string {
+	placeholders := []string{}
+	for k, v := range state.Values {
+		placeholders = append(placeholders, "{"+k+"}", v)
+	}
+	text := strings.NewReplacer(placeholders...).Replace(screen.Text)
+	if text == "" {
+		return []string{}
+	}
+	return []string{text}
+}
+
+// footer returns the navigation choices of a screen
+func (m *USSDMenu) footer(screen *USSDScreen, state *USSDMenuState) []string {
+	lines := []string{}
+	if !screen.DisableNavigation && len(state.History) > 0 {
+		lines = append(lines, fmt.Sprintf("%s. Back", m.BackInput))
+	}
+	if !screen.DisableNavigation && len(state.History) > 1 {
+		lines = append(lines, fmt.Sprintf("%s. Home", m.HomeInput))
+	}
+	return lines
+}
+
+// optionLines returns the numbered options from start to end followed by the
+// more choice when further options remain
+func (m *USSDMenu) optionLines(options []USSDOption, start, end int) []string {
+	lines := []string{}
+	for i := start; i < end; i++ {
+		lines = append(lines, fmt.Sprintf("%d. %s", i+1, options[i].Label))
+	}
+	if end < len(options) {
+		lines = append(lines, fmt.Sprintf("%s. More", m.MoreInput))
+	}
+	return lines
+}
+
+// render builds the text of a screen: the screen text followed by the
+// options on the current page and the navigation choices
+func (m *USSDMenu) render(screen *USSDScreen, state *USSDMenuState) (string, error) {
+	lines := m.header(screen, state)
+	if screen.End {
+		return strings.Join(lines, "\n"), nil
+	}
+
+	options, err := m.options(screen, state)
+	if err != nil {
+		return "", err
+	}
+	pages := m.pages(screen, state, options)
+	if state.Page < len(pages) {
+		end := len(options)
+		if state.Page+1 < len(pages) {
+			end = pages[state.Page+1]
+		}
+		lines = append(lines, m.optionLines(options, pages[state.Page], end)...)
+	}
+	lines = append(lines, m.footer(screen, state)...)
+	return strings.Join(lines, "\n"), nil
+}
+
+// SimulateUSSDSession drives a menu through a dial sequence the way a USSD
+// gateway would: the first request carries no text and every later request
+// carries all the inputs entered so far.
+//
+// It returns the formatted response to every request and is intended for
+// table driven tests of menus.
+func SimulateUSSDSession(
+	menu *USSDMenu, sessionID, phoneNumber string, inputs ...string) ([]string, error) {
+	normalized, err := NormalizeMSISDN(phoneNumber)
+	if err != nil {
+		return nil, fmt.Errorf("invalid phone format: %v", err)
+	}
+	responses := []string{}
+	for i := 0; i <= len(inputs); i++ {
+		req := &USSDRequest{
+			SessionID:   sessionID,
+			ServiceCode: "*000#",
+			PhoneNumber: *normalized,
+			Text:        strings.Join(inputs[:i], USSDInputSeparator),
+			Inputs:      inputs[:i],
+		}
+		resp, err := menu.Handle(req)
+		if err != nil {
+			return responses, err
+		}
+		formatted, err := resp.Format()
+		if err != nil {
+			return responses, err
+		}
+		responses = append(responses, formatted)
+		if resp.End {
+			break
+		}
+	}
+	return responses, nil
+}
@@ -0,0 +1,395 @@
+package converterandformatter_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/savannahghi/converterandformatter"
+	"github.com/stretchr/testify/assert"
+)
+
+func newSignupMenu(t *testing.T) *converterandformatter.USSDMenu {
+	counties := []converterandformatter.USSDOption{}
+	for _, name := range []string{"Nairobi", "Mombasa", "Kisumu", "Nakuru", "Eldoret", "Nyeri", "Machakos"} {
+		counties = append(counties, converterandformatter.USSDOption{Label: name, Next: "CONFIRM"})
+	}
+	menu, err := converterandformatter.NewUSSDMenu(
+		"HOME",
+		converterandformatter.NewInMemoryUSSDMenuStore(),
+		&converterandformatter.USSDScreen{
+			ID:   "HOME",
+			Text: "Welcome",
+			Options: []converterandformatter.USSDOption{
+				{Label: "Register", Next: "NAME"},
+				{Label: "Exit", Next: "BYE"},
+			},
+		},
+		&converterandformatter.USSDScreen{
+			ID:   "NAME",
+			Text: "Enter your name",
+			Key:  "name",
+			Next: "PHONE",
+		},
+		&converterandformatter.USSDScreen{
+			ID:   "PHONE",
+			Text: "Enter next of kin phone",
+			Key:  "kin",
+			Validate: converterandformatter.USSDPredicateValidator(
+				converterandformatter.IsMSISDNValid, "Invalid phone number."),
+			Next: "COUNTY",
+		},
+		&converterandformatter.USSDScreen{
+			ID:      "COUNTY",
+			Text:    "Select county",
+			Key:     "county",
+			Options: counties,
+		},
+		&converterandformatter.USSDScreen{
+			ID:   "CONFIRM",
+			Text: "{name} in {county}",
+			Options: []converterandformatter.USSDOption{
+				{Label: "Confirm", Next: "DONE"},
+			},
+		},
+		&converterandformatter.USSDScreen{
+			ID:   "DONE",
+			Text: "Registered {name}",
+			End:  true,
+		},
+		&converterandformatter.USSDScreen{
+			ID:   "BYE",
+			Text: "Goodbye",
+			End:  true,
+		},
+	)
+	assert.Nil(t, err)
+	return menu
+}
+
+func TestUSSDMenuDialSequences(t *testing.T) {
+	tests := []struct {
+		name   string
+		inputs []string
+		want   []string
+	}{
+		{
+			name:   "exit",
+			inputs: []string{"2"},
+			want: []string{
+				"CON Welcome\n1. Register\n2. Exit",
+				"END Goodbye",
+			},
+		},
+		{
+			name:   "invalid choice",
+			inputs: []string{"7"},
+			want: []string{
+				"CON Welcome\n1. Register\n2. Exit",
+				"CON Invalid choice.\nWelcome\n1. Register\n2. Exit",
+			},
+		},
+		{
+			name:   "full registration with pagination",
+			inputs: []string{"1", "Wanjiku", "0722000000", "98", "7", "1"},
+			want: []string{
+				"CON Welcome\n1. Register\n2. Exit",
+				"CON Enter your name\n0. Back",
+				"CON Enter next of kin phone\n0. Back\n00. Home",
+				"CON Select county\n1. Nairobi\n2. Mombasa\n3. Kisumu\n4. Nakuru\n5. Eldoret\n98. More\n0. Back\n00. Home",
+				"CON Select county\n6. Nyeri\n7. Machakos\n0. Back\n00. Home",
+				"CON Wanjiku in Machakos\n1. Confirm\n0. Back\n00. Home",
+				"END Registered Wanjiku",
+			},
+		},
+		{
+			name:   "validation failure",
+			inputs: []string{"1", "Wanjiku", "12"},
+			want: []string{
+				"CON Welcome\n1. Register\n2. Exit",
+				"CON Enter your name\n0. Back",
+				"CON Enter next of kin phone\n0. Back\n00. Home",
+				"CON Invalid phone number.\nEnter next of kin phone\n0. Back\n00. Home",
+			},
+		},
+		{
+			name:   "back and home navigation",
+			inputs: []string{"1", "Wanjiku", "0", "Otieno", "00"},
+			want: []string{
+				"CON Welcome\n1. Register\n2. Exit",
+				"CON Enter your name\n0. Back",
+				"CON Enter next of kin phone\n0. Back\n00. Home",
+				"CON Enter your name\n0. Back",
+				"CON Enter next of kin phone\n0. Back\n00. Home",
+				"CON Welcome\n1. Register\n2. Exit",
+			},
+		},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			menu := newSignupMenu(t)
+			got, err := converterandformatter.SimulateUSSDSession(
+				menu, fmt.Sprintf("session-%d", i), "0722000000", tt.inputs...)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestUSSDMenuHandlesBatchedInputs(t *testing.T) {
+	menu := newSignupMenu(t)
+	req := &converterandformatter.USSDRequest{
+		SessionID:   "batched",
+		PhoneNumber: "+254722000000",
+		Text:        "1*Wanjiku",
+		Inputs:      []string{"1", "Wanjiku"},
+	}
+	resp, err := menu.Handle(req)
+	assert.Nil(t, err)
+	assert.Equal(t, "Enter next of kin phone\n0. Back\n00. Home", resp.Message)
+	assert.False(t, resp.End)
+}
+
+func TestUSSDMenuEndedSessionIsForgotten(t *testing.T) {
+	store := converterandformatter.NewInMemoryUSSDMenuStore()
+	menu := newSignupMenu(t)
+	menu.Store = store
+
+	_, err := converterandformatter.SimulateUSSDSession(menu, "forgotten", "0722000000", "2")
+	assert.Nil(t, err)
+
+	state, err := store.Get("forgotten")
+	assert.Nil(t, err)
+	assert.Nil(t, state)
+}
+
+func TestUSSDMenuOnEnterAndLoadOptions(t *testing.T) {
+	entered := 0
+	menu, err := converterandformatter.NewUSSDMenu(
+		"HOME",
+		converterandformatter.NewInMemoryUSSDMenuStore(),
+		&converterandformatter.USSDScreen{
+			ID:  "HOME",
+			Key: "choice",
+			LoadOptions: func(values map[string]string) ([]converterandformatter.USSDOption, error) {
+				return []converterandformatter.USSDOption{
+					{Label: "Loaded", Value: "loaded", Next: "DONE"},
+				}, nil
+			},
+		},
+		&converterandformatter.USSDScreen{
+			ID:   "DONE",
+			Text: "You chose {choice}",
+			End:  true,
+			OnEnter: func(state *converterandformatter.USSDMenuState) error {
+				entered++
+				return nil
+			},
+		},
+	)
+	assert.Nil(t, err)
+
+	got, err := converterandformatter.SimulateUSSDSession(menu, "loaded", "0722000000", "1")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"CON 1. Loaded", "END You chose loaded"}, got)
+	assert.Equal(t, 1, entered)
+}
+
+func TestUSSDMenuNavigation(t *testing.T) {
+	entered := map[string]int{}
+	onEnter := func(state *converterandformatter.USSDMenuState) error {
+		entered[state.ScreenID]++
+		return nil
+	}
+	menu, err := converterandformatter.NewUSSDMenu(
+		"HOME",
+		converterandformatter.NewInMemoryUSSDMenuStore(),
+		&converterandformatter.USSDScreen{
+			ID:      "HOME",
+			Text:    "Welcome",
+			OnEnter: onEnter,
+			Options: []converterandformatter.USSDOption{{Label: "Pay", Next: "ACCOUNT"}},
+		},
+		&converterandformatter.USSDScreen{
+			ID:      "ACCOUNT",
+			Text:    "Enter account",
+			Key:     "account",
+			Next:    "AMOUNT",
+			OnEnter: onEnter,
+		},
+		&converterandformatter.USSDScreen{
+			ID:                "AMOUNT",
+			Text:              "Enter tip for {account}",
+			Key:               "tip",
+			Next:              "DONE",
+			DisableNavigation: true,
+		},
+		&converterandformatter.USSDScreen{
+			ID:   "DONE",
+			Text: "Tip of {tip}",
+			End:  true,
+		},
+	)
+	assert.Nil(t, err)
+
+	got, err := converterandformatter.SimulateUSSDSession(
+		menu, "navigation", "0722000000", "1", "0", "1", "00", "1", "A1", "0")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		"CON Welcome\n1. Pay",
+		"CON Enter account\n0. Back",
+		"CON Welcome\n1. Pay",
+		"CON Enter account\n0. Back",
+		"CON Welcome\n1. Pay",
+		"CON Enter account\n0. Back",
+		"CON Enter tip for A1",
+		"END Tip of 0",
+	}, got)
+	assert.Equal(t, map[string]int{"HOME": 3, "ACCOUNT": 3}, entered)
+}
+
+func TestUSSDMenuPaginatesLongLabels(t *testing.T) {
+	facilities := []converterandformatter.USSDOption{}
+	for _, name := range []string{"Kenyatta", "Moi", "Aga Khan", "Coast", "Nakuru"} {
+		facilities = append(facilities, converterandformatter.USSDOption{
+			Label: name + " Teaching and Referral Hospital",
+			Next:  "DONE",
+		})
+	}
+	menu, err := converterandformatter.NewUSSDMenu(
+		"HOME",
+		converterandformatter.NewInMemoryUSSDMenuStore(),
+		&converterandformatter.USSDScreen{
+			ID:      "HOME",
+			Text:    "Select facility",
+			Key:     "facility",
+			Options: facilities,
+		},
+		&converterandformatter.USSDScreen{
+			ID:   "DONE",
+			Text: "Booked at {facility}",
+			End:  true,
+		},
+	)
+	assert.Nil(t, err)
+
+	got, err := converterandformatter.SimulateUSSDSession(
+		menu, "long-labels", "0722000000", "98", "5")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		"CON Select facility\n" +
+			"1. Kenyatta Teaching and Referral Hospital\n" +
+			"2. Moi Teaching and Referral Hospital\n" +
+			"3. Aga Khan Teaching and Referral Hospital\n" +
+			"98. More",
+		"CON Select facility\n" +
+			"4. Coast Teaching and Referral Hospital\n" +
+			"5. Nakuru Teaching and Referral Hospital",
+		"END Booked at Nakuru Teaching and Referral Hospital",
+	}, got)
+	for _, response := range got {
+		assert.LessOrEqual(t, len(response)-len("CON "), converterandformatter.USSDMaxResponseLength)
+	}
+}
+
+func TestUSSDMenuInvalidChoiceOnAFullPage(t *testing.T) {
+	options := []converterandformatter.USSDOption{}
+	for i := 0; i < 10; i++ {
+		options = append(options, converterandformatter.USSDOption{
+			Label: fmt.Sprintf("Sub-county health office %02d", i),
+			Next:  "DONE",
+		})
+	}
+	menu, err := converterandformatter.NewUSSDMenu(
+		"HOME",
+		converterandformatter.NewInMemoryUSSDMenuStore(),
+		&converterandformatter.USSDScreen{ID: "HOME", Text: "Select office", Options: options},
+		&converterandformatter.USSDScreen{ID: "DONE", Text: "Done", End: true},
+	)
+	assert.Nil(t, err)
+
+	got, err := converterandformatter.SimulateUSSDSession(
+		menu, "full-page", "0722000000", "77", "98", "77")
+	assert.Nil(t, err)
+	assert.Len(t, got, 4)
+	assert.Equal(t, "CON Invalid choice.\n"+strings.TrimPrefix(got[0], "CON "), got[1])
+	assert.Equal(t, "CON Invalid choice.\n"+strings.TrimPrefix(got[2], "CON "), got[3])
+	for _, response := range got {
+		assert.LessOrEqual(t, len(response)-len("CON "), converterandformatter.USSDMaxResponseLength)
+	}
+
+	// a validator message that does not fit is shortened
+	long := strings.Repeat("x", converterandformatter.USSDMaxResponseLength)
+	menu, err = converterandformatter.NewUSSDMenu(
+		"HOME",
+		converterandformatter.NewInMemoryUSSDMenuStore(),
+		&converterandformatter.USSDScreen{
+			ID:   "HOME",
+			Text: "Enter code",
+			Validate: func(input string) error {
+				return fmt.Errorf("%s", long)
+			},
+		},
+	)
+	assert.Nil(t, err)
+	got, err = converterandformatter.SimulateUSSDSession(menu, "long-notice", "0722000000", "1")
+	assert.Nil(t, err)
+	assert.Len(t, got, 2)
+	assert.Equal(t, converterandformatter.USSDMaxResponseLength, len(got[1])-len("CON "))
+	assert.True(t, strings.HasSuffix(got[1], "\nEnter code"))
+}
+
+func TestNewUSSDMenu_Invalid(t *testing.T) {
+	store := converterandformatter.NewInMemoryUSSDMenuStore()
+	tests := []struct {
+		name    string
+		start   string
+		store   converterandformatter.USSDMenuStore
+		screens []*converterandformatter.USSDScreen
+	}{
+		{
+			name:    "missing store",
+			start:   "HOME",
+			screens: []*converterandformatter.USSDScreen{{ID: "HOME"}},
+		},
+		{
+			name:    "unknown start screen",
+			start:   "NOWHERE",
+			store:   store,
+			screens: []*converterandformatter.USSDScreen{{ID: "HOME"}},
+		},
+		{
+			name:    "duplicate screen",
+			start:   "HOME",
+			store:   store,
+			screens: []*converterandformatter.USSDScreen{{ID: "HOME"}, {ID: "HOME"}},
+		},
+		{
+			name:    "missing screen ID",
+			start:   "HOME",
+			store:   store,
+			screens: []*converterandformatter.USSDScreen{{ID: "HOME"}, {}},
+		},
+		{
+			name:    "unknown next screen",
+			start:   "HOME",
+			store:   store,
+			screens: []*converterandformatter.USSDScreen{{ID: "HOME", Next: "NOWHERE"}},
+		},
+		{
+			name:  "unknown option screen",
+			start: "HOME",
+			store: store,
+			screens: []*converterandformatter.USSDScreen{{
+				ID:      "HOME",
+				Options: []converterandformatter.USSDOption{{Label: "Go", Next: "NOWHERE"}},
+			}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := converterandformatter.NewUSSDMenu(tt.start, tt.store, tt.screens...)
+			assert.NotNil(t, err)
+		})
+	}
+}
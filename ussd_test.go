@@ -0,0 +1,243 @@
+package converterandformatter_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	uuid "github.com/kevinburke/go.uuid"
+	"github.com/savannahghi/converterandformatter"
+	"github.com/savannahghi/firebasetools"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewUSSDSession(t *testing.T) {
+	type args struct {
+		sessionID   string
+		msisdn      string
+		serviceCode string
+		networkCode string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "valid session",
+			args: args{
+				sessionID:   "ATUid_123",
+				msisdn:      "0722000000",
+				serviceCode: "*384*123#",
+				networkCode: "63902",
+			},
+			want:    "+254722000000",
+			wantErr: false,
+		},
+		{
+			name: "missing session ID",
+			args: args{
+				sessionID: " ",
+				msisdn:    "0722000000",
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid phone number",
+			args: args{
+				sessionID: "ATUid_123",
+				msisdn:    "not a phone",
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := converterandformatter.NewUSSDSession(
+				tt.args.sessionID, tt.args.msisdn, tt.args.serviceCode, tt.args.networkCode)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewUSSDSession() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			assert.Equal(t, tt.want, got.MSISDN)
+			assert.Equal(t, converterandformatter.USSDSessionStatusActive, got.Status)
+			assert.Empty(t, got.Inputs)
+			assert.Nil(t, got.EndedAt)
+		})
+	}
+}
+
+func TestUSSDSessionStatusIsValid(t *testing.T) {
+	assert.True(t, converterandformatter.USSDSessionStatusCompleted.IsValid())
+	assert.Equal(t, "COMPLETED", converterandformatter.USSDSessionStatusCompleted.String())
+	assert.False(t, converterandformatter.USSDSessionStatus("PAUSED").IsValid())
+}
+
+func TestEndUSSDSession_InvalidStatus(t *testing.T) {
+	_, err := converterandformatter.EndUSSDSession(
+		"ATUid_123", converterandformatter.USSDSessionStatusActive, nil)
+	assert.NotNil(t, err)
+}
+
+func TestUSSDSessionLifecycle(t *testing.T) {
+	fc, err := firebasetools.GetFirestoreClient(context.Background())
+	if err != nil {
+		t.Fatalf("unable to initialize Firestore client: %v", err)
+	}
+
+	sessionID := uuid.NewV4().String()
+	session, err := converterandformatter.StartUSSDSession(
+		sessionID, "0722000000", "*384*123#", "63902", fc)
+	assert.Nil(t, err)
+	assert.Equal(t, converterandformatter.USSDSessionStatusActive, session.Status)
+
+	_, err = converterandformatter.StartUSSDSession(
+		sessionID, "0722000000", "*384*123#", "63902", fc)
+	assert.NotNil(t, err)
+
+	session, err = converterandformatter.UpdateUSSDSession(sessionID, "MAIN_MENU", "", fc)
+	assert.Nil(t, err)
+	assert.Equal(t, "MAIN_MENU", session.MenuState)
+	assert.Empty(t, session.Inputs)
+
+	session, err = converterandformatter.UpdateUSSDSession(sessionID, "ENTER_NAME", "1", fc)
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"1"}, session.Inputs)
+
+	session, err = converterandformatter.EndUSSDSession(
+		sessionID, converterandformatter.USSDSessionStatusCompleted, fc)
+	assert.Nil(t, err)
+	assert.NotNil(t, session.EndedAt)
+
+	_, err = converterandformatter.UpdateUSSDSession(sessionID, "MAIN_MENU", "2", fc)
+	assert.NotNil(t, err)
+
+	_, err = converterandformatter.EndUSSDSession(
+		sessionID, converterandformatter.USSDSessionStatusFailed, fc)
+	assert.NotNil(t, err)
+}
+
+func TestVerifyUSSDSession_InvalidInput(t *testing.T) {
+	_, err := converterandformatter.VerifyUSSDSession("ATUid_123", "not a phone", nil)
+	assert.NotNil(t, err)
+
+	_, err = converterandformatter.VerifyUSSDSession(" ", "0722000000", nil)
+	assert.NotNil(t, err)
+}
+
+func TestUSSDSession_VerifyAt(t *testing.T) {
+	now := time.Now()
+	ended := now.Add(-time.Minute)
+	tests := []struct {
+		name    string
+		session converterandformatter.USSDSession
+		msisdn  string
+		wantErr bool
+	}{
+		{
+			name: "active and recent",
+			session: converterandformatter.USSDSession{
+				SessionID: "ATUid_123",
+				MSISDN:    "+254722000000",
+				Status:    converterandformatter.USSDSessionStatusActive,
+				UpdatedAt: now.Add(-time.Minute),
+			},
+			msisdn:  "+254722000000",
+			wantErr: false,
+		},
+		{
+			name: "dialled by another phone number",
+			session: converterandformatter.USSDSession{
+				SessionID: "ATUid_123",
+				MSISDN:    "+254722000000",
+				Status:    converterandformatter.USSDSessionStatusActive,
+				UpdatedAt: now,
+			},
+			msisdn:  "+254733000000",
+			wantErr: true,
+		},
+		{
+			name: "ended",
+			session: converterandformatter.USSDSession{
+				SessionID: "ATUid_123",
+				MSISDN:    "+254722000000",
+				Status:    converterandformatter.USSDSessionStatusCompleted,
+				UpdatedAt: ended,
+				EndedAt:   &ended,
+			},
+			msisdn:  "+254722000000",
+			wantErr: true,
+		},
+		{
+			name: "stale",
+			session: converterandformatter.USSDSession{
+				SessionID: "ATUid_123",
+				MSISDN:    "+254722000000",
+				Status:    converterandformatter.USSDSessionStatusActive,
+				UpdatedAt: now.Add(-converterandformatter.USSDSessionVerificationWindow - time.Second),
+			},
+			msisdn:  "+254722000000",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.session.VerifyAt(tt.msisdn, now)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("USSDSession.VerifyAt() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifyUSSDSession(t *testing.T) {
+	fc, err := firebasetools.GetFirestoreClient(context.Background())
+	if err != nil {
+		t.Fatalf("unable to initialize Firestore client: %v", err)
+	}
+
+	active, err := converterandformatter.StartUSSDSession(
+		uuid.NewV4().String(), "0722000000", "*384*123#", "63902", fc)
+	assert.Nil(t, err)
+	msisdn, err := converterandformatter.VerifyUSSDSession(active.SessionID, "0722000000", fc)
+	assert.Nil(t, err)
+	assert.Equal(t, "+254722000000", msisdn)
+
+	_, err = converterandformatter.VerifyUSSDSession(active.SessionID, "0733000000", fc)
+	assert.NotNil(t, err)
+
+	ended, err := converterandformatter.StartUSSDSession(
+		uuid.NewV4().String(), "0722000000", "*384*123#", "63902", fc)
+	assert.Nil(t, err)
+	_, err = converterandformatter.EndUSSDSession(
+		ended.SessionID, converterandformatter.USSDSessionStatusCompleted, fc)
+	assert.Nil(t, err)
+	_, err = converterandformatter.VerifyUSSDSession(ended.SessionID, "0722000000", fc)
+	assert.NotNil(t, err)
+
+	stale, err := converterandformatter.NewUSSDSession(
+		uuid.NewV4().String(), "0722000000", "*384*123#", "63902")
+	assert.Nil(t, err)
+	stale.UpdatedAt = time.Now().Add(-2 * converterandformatter.USSDSessionVerificationWindow)
+	err = firebasetools.UpdateRecordOnFirestore(
+		fc, converterandformatter.CollectionName(converterandformatter.USSDSessionCollectionName),
+		stale.SessionID, stale)
+	assert.Nil(t, err)
+	_, err = converterandformatter.VerifyUSSDSession(stale.SessionID, "0722000000", fc)
+	assert.NotNil(t, err)
+
+	legacy := converterandformatter.USSDSessionLog{
+		MSISDN:    "+254722000000",
+		SessionID: uuid.NewV4().String(),
+	}
+	_, err = firebasetools.SaveDataToFirestore(
+		fc, converterandformatter.CollectionName(converterandformatter.USSDSessionCollectionName), legacy)
+	assert.Nil(t, err)
+	// a log record has no status to check, so it does not verify a number
+	_, err = converterandformatter.VerifyUSSDSession(legacy.SessionID, "0722000000", fc)
+	assert.NotNil(t, err)
+}
@@ -11,17 +11,20 @@ import (
 	"github.com/ttacon/libphonenumber"
 )
 
+// the MSISDN patterns are compiled once since validation sits on the hot path
+// of bulk imports
+var (
+	kenyanMSISDNPattern        = regexp.MustCompile(`^(?:254|\+254|0)?((7|1)(?:(?:[129][0-9])|(?:0[0-8])|(4[0-1]))[0-9]{6})$`)
+	internationalMSISDNPattern = regexp.MustCompile(`^(?:(?:\(?(?:00|\+)([1-4]\d\d|[1-9]\d?)\)?)?[\-\.\ \\\/]?)?((?:\(?\d{1,}\)?[\-\.\ \\\/]?){0,})(?:[\-\.\ \\\/]?(?:#|ext\.?|extension|x)[\-\.\ \\\/]?(\d+))?$`)
+)
+
 // IsMSISDNValid uses regular expression to validate the a phone number
 func IsMSISDNValid(msisdn string) bool {
 	if len(msisdn) < 10 {
 		return false
 	}
-	reKen := regexp.MustCompile(`^(?:254|\+254|0)?((7|1)(?:(?:[129][0-9])|(?:0[0-8])|(4[0-1]))[0-9]{6})$`)
-	re := regexp.MustCompile(`^(?:(?:\(?(?:00|\+)([1-4]\d\d|[1-9]\d?)\)?)?[\-\.\ \\\/]?)?((?:\(?\d{1,}\)?[\-\.\ \\\/]?){0,})(?:[\-\.\ \\\/]?(?:#|ext\.?|extension|x)[\-\.\ \\\/]?(\d+))?$`)
-	if !reKen.MatchString(msisdn) {
-		return re.MatchString(msisdn)
-	}
-	return reKen.MatchString(msisdn)
+	return kenyanMSISDNPattern.MatchString(msisdn) ||
+		internationalMSISDNPattern.MatchString(msisdn)
 }
 
 // NormalizeMSISDN validates the input phone number.
@@ -42,7 +45,11 @@ func NormalizeMSISDN(msisdn string) (*string, error) {
 }
 
 // ValidateMSISDN returns an error if the MSISDN format is wrong or the
-// supplied verification code is not valid
+// supplied verification code is not valid.
+//
+// When isUSSD is true the verification code is the ID of a USSD session and
+// the MSISDN is trusted only if that session has been recorded (see
+// VerifyUSSDSession).
 // Deprecated: Should implement `VerifyOTP` instead. This helps to confirm if a phonenumber
 // is valid by verifying the code sent to it.
 func ValidateMSISDN(
@@ -55,22 +62,13 @@ func ValidateMSISDN(
 		return "", fmt.Errorf("invalid phone format: %v", err)
 	}
 
-	// save a USSD log for USSD registrations
+	// USSD registrations are trusted through the session the telco opened
 	if isUSSD {
-		log := USSDSessionLog{
-			MSISDN:    msisdn,
-			SessionID: verificationCode,
-		}
-		_, err = firebasetools.SaveDataToFirestore(
-			firestoreClient, firebasetools.SuffixCollection(USSDSessionCollectionName), log)
-		if err != nil {
-			return "", fmt.Errorf("unable to save USSD session: %v", err)
-		}
-		return *normalized, nil
+		return VerifyUSSDSession(verificationCode, *normalized, firestoreClient)
 	}
 
 	// check if the OTP is on file / known
-	query := firestoreClient.Collection(firebasetools.SuffixCollection(OTPCollectionName)).Where(
+	query := firestoreClient.Collection(CollectionName(OTPCollectionName)).Where(
 		"isValid", "==", true,
 	).Where(
 		"msisdn", "==", normalized,
@@ -90,7 +88,7 @@ func ValidateMSISDN(
 		otpData := doc.Data()
 		otpData["isValid"] = false
 		err = firebasetools.UpdateRecordOnFirestore(
-			firestoreClient, firebasetools.SuffixCollection(OTPCollectionName), doc.Ref.ID, otpData)
+			firestoreClient, CollectionName(OTPCollectionName), doc.Ref.ID, otpData)
 		if err != nil {
 			return "", fmt.Errorf("unable to save updated OTP document: %v", err)
 		}
@@ -115,7 +113,7 @@ func ValidateAndSaveMSISDN(
 			OptedIn: optIn,
 		}
 		_, err = firebasetools.SaveDataToFirestore(
-			firestoreClient, PhoneOptInCollectionName, data)
+			firestoreClient, CollectionName(PhoneOptInCollectionName), data)
 		if err != nil {
 			return "", fmt.Errorf("unable to save email opt in: %v", err)
 		}
@@ -124,21 +122,15 @@ func ValidateAndSaveMSISDN(
 }
 
 // StringSliceContains tests if a string is contained in a slice of strings
+//
+// Deprecated: use Contains.
 func StringSliceContains(s []string, e string) bool {
-	for _, a := range s {
-		if a == e {
-			return true
-		}
-	}
-	return false
+	return Contains(s, e)
 }
 
-// IntSliceContains tests if a string is contained in a slice of strings
+// IntSliceContains tests if an int is contained in a slice of ints
+//
+// Deprecated: use Contains.
 func IntSliceContains(s []int, e int) bool {
-	for _, a := range s {
-		if a == e {
-			return true
-		}
-	}
-	return false
+	return Contains(s, e)
 }
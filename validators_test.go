@@ -6,6 +6,7 @@ import (
 	"log"
 	"math/rand"
 	"os"
+	"regexp"
 	"strconv"
 	"testing"
 	"time"
@@ -221,7 +222,7 @@ func TestValidateMSISDN(t *testing.T) {
 		"msisdn":            normalized,
 		"timestamp":         time.Now(),
 	}
-	_, err = firebasetools.SaveDataToFirestore(firestoreClient, firebasetools.SuffixCollection(converterandformatter.OTPCollectionName), validOtpData)
+	_, err = firebasetools.SaveDataToFirestore(firestoreClient, converterandformatter.CollectionName(converterandformatter.OTPCollectionName), validOtpData)
 	assert.Nil(t, err)
 
 	invalidOtpCode := rand.Int()
@@ -232,7 +233,10 @@ func TestValidateMSISDN(t *testing.T) {
 		"msisdn":            normalized,
 		"timestamp":         time.Now(),
 	}
-	_, err = firebasetools.SaveDataToFirestore(firestoreClient, firebasetools.SuffixCollection(converterandformatter.OTPCollectionName), invalidOtpData)
+	_, err = firebasetools.SaveDataToFirestore(firestoreClient, converterandformatter.CollectionName(converterandformatter.OTPCollectionName), invalidOtpData)
+	assert.Nil(t, err)
+
+	ussdSession, err := converterandformatter.StartUSSDSession(uuid.NewV1().String(), otpMsisdn, "*384*123#", "63902", firestoreClient)
 	assert.Nil(t, err)
 
 	type args struct {
@@ -259,13 +263,35 @@ func TestValidateMSISDN(t *testing.T) {
 			name: "ussd session validation",
 			args: args{
 				msisdn:           "0722000000",
-				verificationCode: uuid.NewV1().String(),
+				verificationCode: ussdSession.SessionID,
 				isUSSD:           true,
 				firestoreClient:  firestoreClient,
 			},
 			want:    "+254722000000",
 			wantErr: false,
 		},
+		{
+			name: "unknown ussd session",
+			args: args{
+				msisdn:           "0722000000",
+				verificationCode: uuid.NewV1().String(),
+				isUSSD:           true,
+				firestoreClient:  firestoreClient,
+			},
+			want:    "",
+			wantErr: true,
+		},
+		{
+			name: "ussd session dialled by another phone",
+			args: args{
+				msisdn:           "0711000000",
+				verificationCode: ussdSession.SessionID,
+				isUSSD:           true,
+				firestoreClient:  firestoreClient,
+			},
+			want:    "",
+			wantErr: true,
+		},
 		{
 			name: "non existent verification code for non USSD",
 			args: args{
@@ -317,6 +343,9 @@ func TestValidateMSISDN(t *testing.T) {
 func TestValidateAndSaveMSISDN(t *testing.T) {
 	fc, _ := firebasetools.GetFirestoreClient(context.Background())
 
+	ussdSession, err := converterandformatter.StartUSSDSession(uuid.NewV1().String(), "0722000000", "*384*123#", "63902", fc)
+	assert.Nil(t, err)
+
 	type args struct {
 		msisdn           string
 		verificationCode string
@@ -346,7 +375,7 @@ func TestValidateAndSaveMSISDN(t *testing.T) {
 			name: "valid phone number, USSD, opt in true",
 			args: args{
 				msisdn:           "0722000000",
-				verificationCode: "this is a ussd session ID from the telco",
+				verificationCode: ussdSession.SessionID,
 				isUSSD:           true,
 				optIn:            true,
 				firestoreClient:  fc,
@@ -358,7 +387,7 @@ func TestValidateAndSaveMSISDN(t *testing.T) {
 			name: "valid phone number, USSD, opt in false",
 			args: args{
 				msisdn:           "0722000000",
-				verificationCode: "this is a ussd session ID from the telco",
+				verificationCode: ussdSession.SessionID,
 				isUSSD:           true,
 				optIn:            false,
 				firestoreClient:  fc,
@@ -366,6 +395,18 @@ func TestValidateAndSaveMSISDN(t *testing.T) {
 			want:    "+254722000000",
 			wantErr: false,
 		},
+		{
+			name: "valid phone number, unknown USSD session",
+			args: args{
+				msisdn:           "0722000000",
+				verificationCode: "this is not a recorded ussd session ID",
+				isUSSD:           true,
+				optIn:            true,
+				firestoreClient:  fc,
+			},
+			want:    "",
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -452,3 +493,40 @@ func TestIntSliceContains(t *testing.T) {
 		})
 	}
 }
+
+var benchmarkMSISDNs = []string{
+	"+254722000000",
+	"0722 000 000",
+	"0110000000",
+	"+1 (202) 555-0143",
+	"not a phone number",
+}
+
+func BenchmarkIsMSISDNValid(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		converterandformatter.IsMSISDNValid(benchmarkMSISDNs[i%len(benchmarkMSISDNs)])
+	}
+}
+
+// BenchmarkIsMSISDNValid_CompilePerCall is the baseline for
+// BenchmarkIsMSISDNValid: it compiles both patterns on every call, the way
+// IsMSISDNValid used to.
+func BenchmarkIsMSISDNValid_CompilePerCall(b *testing.B) {
+	isValid := func(msisdn string) bool {
+		reKen := regexp.MustCompile(`^(?:254|\+254|0)?((7|1)(?:(?:[129][0-9])|(?:0[0-8])|(4[0-1]))[0-9]{6})$`)
+		re := regexp.MustCompile(`^(?:(?:\(?(?:00|\+)([1-4]\d\d|[1-9]\d?)\)?)?[\-\.\ \\\/]?)?((?:\(?\d{1,}\)?[\-\.\ \\\/]?){0,})(?:[\-\.\ \\\/]?(?:#|ext\.?|extension|x)[\-\.\ \\\/]?(\d+))?$`)
+		if !reKen.MatchString(msisdn) {
+			return re.MatchString(msisdn)
+		}
+		return reKen.MatchString(msisdn)
+	}
+	for i := 0; i < b.N; i++ {
+		isValid(benchmarkMSISDNs[i%len(benchmarkMSISDNs)])
+	}
+}
+
+func BenchmarkNormalizeMSISDN(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = converterandformatter.NormalizeMSISDN(benchmarkMSISDNs[i%len(benchmarkMSISDNs)])
+	}
+}
@@ -0,0 +1,280 @@
+package converterandformatter
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Language is an ISO 639-1 code for a language that text can be written in
+type Language string
+
+// the languages that text can be written in
+const (
+	LanguageEnglish Language = "en"
+	LanguageSwahili Language = "sw"
+)
+
+// AllLanguages is a list of all the known languages
+var AllLanguages = []Language{
+	LanguageEnglish,
+	LanguageSwahili,
+}
+
+// IsValid returns true if the language is a known language
+func (l Language) IsValid() bool {
+	switch l {
+	case LanguageEnglish, LanguageSwahili:
+		return true
+	}
+	return false
+}
+
+func (l Language) String() string {
+	return string(l)
+}
+
+// maxNumberInWords is one more than the largest number (999 trillion...)
+// that can be written in words
+const maxNumberInWords = 1000000000000000
+
+var englishUnits = []string{
+	"zero", "one", "two", "three", "four", "five", "six", "seven", "eight",
+	"nine", "ten", "eleven", "twelve", "thirteen", "fourteen", "fifteen",
+	"sixteen", "seventeen", "eighteen", "nineteen",
+}
+
+var englishTens = []string{
+	"", "", "twenty", "thirty", "forty", "fifty", "sixty", "seventy",
+	"eighty", "ninety",
+}
+
+var swahiliUnits = []string{
+	"sifuri", "moja", "mbili", "tatu", "nne", "tano", "sita", "saba", "nane",
+	"tisa",
+}
+
+var swahiliTens = []string{
+	"", "kumi", "ishirini", "thelathini", "arobaini", "hamsini", "sitini",
+	"sabini", "themanini", "tisini",
+}
+
+// the words for thousands, millions, billions and trillions, smallest first
+var (
+	englishScales = []string{"", "thousand", "million", "billion", "trillion"}
+	swahiliScales = []string{"", "elfu", "milioni", "bilioni", "trilioni"}
+)
+
+// NumberToWords writes a number, up to 999 trillion in either direction, in
+// words e.g "one thousand two hundred and fifty" or "elfu moja mia mbili na
+// hamsini"
+func NumberToWords(n int64, lang Language) (string, error) {
+	if !lang.IsValid() {
+		return "", fmt.Errorf("unknown language: %s", lang)
+	}
+	if n <= -maxNumberInWords || n >= maxNumberInWords {
+		return "", fmt.Errorf("%d is too large to write in words", n)
+	}
+	if n < 0 {
+		words, _ := NumberToWords(-n, lang)
+		if lang == LanguageSwahili {
+			return "hasi " + words, nil
+		}
+		return "minus " + words, nil
+	}
+	if lang == LanguageSwahili {
+		return swahiliNumberToWords(n), nil
+	}
+	return englishNumberToWords(n), nil
+}
+
+// splitThousands splits a number into groups of three digits, smallest first
+func splitThousands(n int64) []int {
+	groups := []int{}
+	for n > 0 {
+		groups = append(groups, int(n%1000))
+		n /= 1000
+	}
+	return groups
+}
+
+func englishNumberToWords(n int64) string {
+	if n == 0 {
+		return englishUnits[0]
+	}
+	groups := splitThousands(n)
+	words := []string{}
+	for scale := len(groups) - 1; scale >= 0; scale-- {
+		group := groups[scale]
+		if group == 0 {
+			continue
+		}
+		// British usage joins a trailing number below one hundred with "and"
+		// e.g one thousand and five
+		if scale == 0 && group < 100 && len(words) > 0 {
+			words = append(words, "and")
+		}
+		words = append(words, englishHundreds(group))
+		if scale > 0 {
+			words = append(words, englishScales[scale])
+		}
+	}
+	return strings.Join(words, " ")
+}
+
+// englishHundreds writes a number between 1 and 999
+func englishHundreds(n int) string {
+	words := []string{}
+	if n >= 100 {
+		words = append(words, englishUnits[n/100], "hundred")
+		n %= 100
+		if n > 0 {
+			words = append(words, "and")
+		}
+	}
+	switch {
+	case n == 0:
+	case n < 20:
+		words = append(words, englishUnits[n])
+	case n%10 == 0:
+		words = append(words, englishTens[n/10])
+	default:
+		words = append(words, englishTens[n/10]+"-"+englishUnits[n%10])
+	}
+	return strings.Join(words, " ")
+}
+
+func swahiliNumberToWords(n int64) string {
+	if n == 0 {
+		return swahiliUnits[0]
+	}
+	groups := splitThousands(n)
+	words := []string{}
+	for scale := len(groups) - 1; scale >= 0; scale-- {
+		group := groups[scale]
+		if group == 0 {
+			continue
+		}
+		if scale > 0 {
+			// the scale comes before its count e.g elfu mbili (two thousand)
+			words = append(words, swahiliScales[scale], swahiliHundreds(group))
+			continue
+		}
+		// a trailing number below one hundred is joined with "na" e.g elfu
+		// moja na tano (one thousand and five)
+		if group < 100 && len(words) > 0 {
+			words = append(words, "na")
+		}
+		words = append(words, swahiliHundreds(group))
+	}
+	return strings.Join(words, " ")
+}
+
+// swahiliHundreds writes a number between 1 and 999
+func swahiliHundreds(n int) string {
+	words := []string{}
+	if n >= 100 {
+		words = append(words, "mia", swahiliUnits[n/100])
+		n %= 100
+		if n > 0 {
+			words = append(words, "na")
+		}
+	}
+	switch {
+	case n == 0:
+	case n < 10:
+		words = append(words, swahiliUnits[n])
+	case n%10 == 0:
+		words = append(words, swahiliTens[n/10])
+	default:
+		words = append(words, swahiliTens[n/10], "na", swahiliUnits[n%10])
+	}
+	return strings.Join(words, " ")
+}
+
+// currencyWords are the names of a currency's major and minor units
+type currencyWords struct {
+	// English singular and plural names
+	major, majorPlural string
+	minor, minorPlural string
+	// Swahili names, which do not change in the plural
+	swahiliMajor, swahiliMinor string
+}
+
+var currencyNames = map[Currency]currencyWords{
+	CurrencyKES: {"shilling", "shillings", "cent", "cents", "shilingi", "senti"},
+	CurrencyUGX: {"shilling", "shillings", "", "", "shilingi", ""},
+	CurrencyTZS: {"shilling", "shillings", "cent", "cents", "shilingi", "senti"},
+	CurrencyRWF: {"franc", "francs", "", "", "faranga", ""},
+	CurrencyUSD: {"dollar", "dollars", "cent", "cents", "dola", "senti"},
+}
+
+// MoneyToWords writes an amount in words, as required on invoices and
+// receipts, e.g "One thousand two hundred shillings and fifty cents" or
+// "Shilingi elfu moja mia mbili na senti hamsini"
+func MoneyToWords(m Money, lang Language) (string, error) {
+	names, ok := currencyNames[m.Currency]
+	if !ok {
+		return "", fmt.Errorf("unknown currency: %s", m.Currency)
+	}
+	amount := m.Amount
+	prefix := ""
+	if amount < 0 {
+		amount = -amount
+		prefix = "minus "
+		if lang == LanguageSwahili {
+			prefix = "hasi "
+		}
+	}
+	divisor := int64(1)
+	for i := 0; i < m.Currency.MinorUnits(); i++ {
+		divisor *= 10
+	}
+	major, minor := amount/divisor, amount%divisor
+
+	majorWords, err := NumberToWords(major, lang)
+	if err != nil {
+		return "", err
+	}
+	minorWords, _ := NumberToWords(minor, lang)
+
+	var words string
+	if lang == LanguageSwahili {
+		words = names.swahiliMajor + " " + majorWords
+		if major == 0 && minor > 0 {
+			words = ""
+		}
+		if minor > 0 {
+			words = strings.TrimPrefix(
+				words+" na "+names.swahiliMinor+" "+minorWords, " na ")
+		}
+	} else {
+		words = majorWords + " " + plural(major, names.major, names.majorPlural)
+		if major == 0 && minor > 0 {
+			words = ""
+		}
+		if minor > 0 {
+			words = strings.TrimPrefix(
+				words+" and "+minorWords+" "+plural(minor, names.minor, names.minorPlural),
+				" and ")
+		}
+	}
+	return capitalize(prefix + words), nil
+}
+
+func plural(n int64, singular, plural string) string {
+	if n == 1 {
+		return singular
+	}
+	return plural
+}
+
+// capitalize upper cases the first letter of some text
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return s
+	}
+	return string(unicode.ToUpper(r)) + s[size:]
+}
@@ -0,0 +1,175 @@
+package converterandformatter_test
+
+import (
+	"testing"
+
+	"github.com/savannahghi/converterandformatter"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNumberToWords(t *testing.T) {
+	tests := []struct {
+		name    string
+		n       int64
+		english string
+		swahili string
+	}{
+		{name: "zero", n: 0, english: "zero", swahili: "sifuri"},
+		{name: "unit", n: 7, english: "seven", swahili: "saba"},
+		{name: "teen", n: 13, english: "thirteen", swahili: "kumi na tatu"},
+		{name: "round tens", n: 40, english: "forty", swahili: "arobaini"},
+		{name: "tens", n: 99, english: "ninety-nine", swahili: "tisini na tisa"},
+		{name: "hundred", n: 100, english: "one hundred", swahili: "mia moja"},
+		{
+			name:    "hundreds",
+			n:       105,
+			english: "one hundred and five",
+			swahili: "mia moja na tano",
+		},
+		{
+			name:    "thousands",
+			n:       1200,
+			english: "one thousand two hundred",
+			swahili: "elfu moja mia mbili",
+		},
+		{
+			name:    "thousands and units",
+			n:       1005,
+			english: "one thousand and five",
+			swahili: "elfu moja na tano",
+		},
+		{
+			name:    "tens of thousands",
+			n:       25250,
+			english: "twenty-five thousand two hundred and fifty",
+			swahili: "elfu ishirini na tano mia mbili na hamsini",
+		},
+		{
+			name:    "hundreds of thousands",
+			n:       300000,
+			english: "three hundred thousand",
+			swahili: "elfu mia tatu",
+		},
+		{
+			name:    "millions",
+			n:       2000050,
+			english: "two million and fifty",
+			swahili: "milioni mbili na hamsini",
+		},
+		{
+			name:    "billions",
+			n:       7000001000,
+			english: "seven billion one thousand",
+			swahili: "bilioni saba elfu moja",
+		},
+		{
+			name:    "largest",
+			n:       999999999999999,
+			english: "nine hundred and ninety-nine trillion nine hundred and ninety-nine billion nine hundred and ninety-nine million nine hundred and ninety-nine thousand nine hundred and ninety-nine",
+			swahili: "trilioni mia tisa na tisini na tisa bilioni mia tisa na tisini na tisa milioni mia tisa na tisini na tisa elfu mia tisa na tisini na tisa mia tisa na tisini na tisa",
+		},
+		{name: "negative", n: -12, english: "minus twelve", swahili: "hasi kumi na mbili"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := converterandformatter.NumberToWords(tt.n, converterandformatter.LanguageEnglish)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.english, got)
+
+			got, err = converterandformatter.NumberToWords(tt.n, converterandformatter.LanguageSwahili)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.swahili, got)
+		})
+	}
+
+	_, err := converterandformatter.NumberToWords(1000000000000000, converterandformatter.LanguageEnglish)
+	assert.NotNil(t, err)
+	_, err = converterandformatter.NumberToWords(-1000000000000000, converterandformatter.LanguageEnglish)
+	assert.NotNil(t, err)
+	_, err = converterandformatter.NumberToWords(1, converterandformatter.Language("fr"))
+	assert.NotNil(t, err)
+}
+
+func TestMoneyToWords(t *testing.T) {
+	tests := []struct {
+		name    string
+		money   converterandformatter.Money
+		english string
+		swahili string
+	}{
+		{
+			name:    "shillings",
+			money:   converterandformatter.Money{Amount: 120000, Currency: converterandformatter.CurrencyKES},
+			english: "One thousand two hundred shillings",
+			swahili: "Shilingi elfu moja mia mbili",
+		},
+		{
+			name:    "shillings and cents",
+			money:   converterandformatter.Money{Amount: 120050, Currency: converterandformatter.CurrencyKES},
+			english: "One thousand two hundred shillings and fifty cents",
+			swahili: "Shilingi elfu moja mia mbili na senti hamsini",
+		},
+		{
+			name:    "cents only",
+			money:   converterandformatter.Money{Amount: 1, Currency: converterandformatter.CurrencyKES},
+			english: "One cent",
+			swahili: "Senti moja",
+		},
+		{
+			name:    "one shilling",
+			money:   converterandformatter.Money{Amount: 100, Currency: converterandformatter.CurrencyKES},
+			english: "One shilling",
+			swahili: "Shilingi moja",
+		},
+		{
+			name:    "zero",
+			money:   converterandformatter.Money{Currency: converterandformatter.CurrencyKES},
+			english: "Zero shillings",
+			swahili: "Shilingi sifuri",
+		},
+		{
+			name:    "no minor units",
+			money:   converterandformatter.Money{Amount: 25000, Currency: converterandformatter.CurrencyUGX},
+			english: "Twenty-five thousand shillings",
+			swahili: "Shilingi elfu ishirini na tano",
+		},
+		{
+			name:    "dollars",
+			money:   converterandformatter.Money{Amount: -1205, Currency: converterandformatter.CurrencyUSD},
+			english: "Minus twelve dollars and five cents",
+			swahili: "Hasi dola kumi na mbili na senti tano",
+		},
+		{
+			name:    "francs",
+			money:   converterandformatter.Money{Amount: 1, Currency: converterandformatter.CurrencyRWF},
+			english: "One franc",
+			swahili: "Faranga moja",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := converterandformatter.MoneyToWords(tt.money, converterandformatter.LanguageEnglish)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.english, got)
+
+			got, err = converterandformatter.MoneyToWords(tt.money, converterandformatter.LanguageSwahili)
+			assert.Nil(t, err)
+			assert.Equal(t, tt.swahili, got)
+		})
+	}
+
+	_, err := converterandformatter.MoneyToWords(
+		converterandformatter.Money{Amount: 1, Currency: "XYZ"}, converterandformatter.LanguageEnglish)
+	assert.NotNil(t, err)
+	_, err = converterandformatter.MoneyToWords(
+		converterandformatter.Money{Amount: 1, Currency: converterandformatter.CurrencyKES}, "fr")
+	assert.NotNil(t, err)
+}
+
+func TestLanguage(t *testing.T) {
+	for _, lang := range converterandformatter.AllLanguages {
+		assert.True(t, lang.IsValid())
+	}
+	assert.False(t, converterandformatter.Language("fr").IsValid())
+	assert.Equal(t, "sw", converterandformatter.LanguageSwahili.String())
+}